@@ -1,11 +1,18 @@
 package spanner
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // App is the top level for a chat application.
 // Call Run with an event handling function to start the application.
+// RunContext may be used instead to stop the application when a context is cancelled.
+// Shutdown stops the application gracefully, finishing any events that are in flight.
 type App interface {
 	Run(EventHandlerFunc) error
+	RunContext(context.Context, EventHandlerFunc) error
+	Shutdown(context.Context) error
 	SendCustom(context.Context, CustomEvent) error
 }
 
@@ -22,10 +29,28 @@ type Event interface {
 	ReceiveConnected() bool
 	ReceiveCustomEvent() CustomEvent
 	ReceiveMessage() ReceivedMessage
+	ReceiveAppMention() AppMention
+	ReceiveReactionAdded() Reaction
+	ReceiveReactionRemoved() Reaction
 	ReceiveSlashCommand(command string) SlashCommand
+	ReceiveGlobalShortcut(callbackID string) GlobalShortcut
+	ReceiveMessageShortcut(callbackID string) MessageShortcut
+	ReceiveAppHomeOpened() HomeTab
 
 	JoinChannel(channelID string)
+	CreateChannel(name string, private bool) ChannelHandle
+	SetChannelTopic(channelID string, topic string) HasError
+	SetChannelPurpose(channelID string, purpose string) HasError
+	InviteToChannel(channelID string, userIDs ...string) HasError
+	KickFromChannel(channelID string, userIDs ...string) HasError
+	ArchiveChannel(channelID string) HasError
+	LeaveChannel(channelID string) HasError
 	SendMessage(channelID string) Message
+	SendEphemeral(userID string, channelID string) Message
+	SendDirectMessage(userID string) Message
+	UploadFile(channelID string, name string, content io.Reader) HasError
+	AddReaction(emoji string, channelID string, timestamp string) HasError
+	RemoveReaction(emoji string, channelID string, timestamp string) HasError
 }
 
 // Metadata provides information common to all events.
@@ -47,6 +72,22 @@ type SlashCommand interface {
 	ModalCreator
 }
 
+// GlobalShortcut represents a global shortcut being triggered.
+// Modal views may be created in response to the shortcut.
+type GlobalShortcut interface {
+	Metadata
+	ModalCreator
+}
+
+// MessageShortcut represents a shortcut being triggered on a message.
+// Message returns the message the shortcut was triggered on.
+// Modal views may be created in response to the shortcut.
+type MessageShortcut interface {
+	Metadata
+	ModalCreator
+	Message() ReceivedMessage
+}
+
 // Modal represents a Slack modal view.
 // It can be used to create blocks and handle submission or closing of the modal.
 type Modal interface {
@@ -58,14 +99,54 @@ type Modal interface {
 
 // ModalSubmission handles a modal being submitted.
 // It can be used to send a response message or push a new modal onto the stack.
+// UpdateModal replaces the submitted modal with a new view, and Close closes only the submitted modal,
+// rather than the whole stack.
+// Reject keeps the modal open and displays errors next to the provided fields.
+// Fields are identified by the ID set with WithID, or by their label.
 type ModalSubmission interface {
 	PushModal(title string) Modal
+	UpdateModal(title string) Modal
+	Close()
+	Reject(errors map[string]string)
+}
+
+// HomeTab represents the Home tab of the app for the user who opened it.
+// Blocks added to the Home tab are published when the event is finished.
+type HomeTab interface {
+	BlockUI
+	Metadata
 }
 
 // ReceivedMessage represents a message received from Slack.
+// ThreadTS returns the timestamp of the thread the message was posted in, or an empty string if
+// it was not posted in a thread.
+// Reply creates a message that will be posted in the message's thread, starting a new thread if needed.
+// ReplyBroadcast does the same, but the reply is also shown in the channel.
 type ReceivedMessage interface {
 	Metadata
 	Text() string
+	TS() string
+	ThreadTS() string
+	IsThreadReply() bool
+	Reply() Message
+	ReplyBroadcast() Message
+}
+
+// AppMention represents a message that mentions the app.
+// Text returns the text of the message with the leading mention of the app removed.
+// Args splits this text into the space-separated arguments that follow the mention.
+type AppMention interface {
+	ReceivedMessage
+	Args() []string
+}
+
+// Reaction represents an emoji reaction being added to or removed from a message.
+// User returns the user who reacted and Channel returns the channel containing the message.
+// Emoji is the name of the emoji, without colons, and ItemTS is the timestamp of the message.
+type Reaction interface {
+	Metadata
+	Emoji() string
+	ItemTS() string
 }
 
 type EphemeralSender interface {
@@ -74,11 +155,15 @@ type EphemeralSender interface {
 
 // Message represents a message that can be sent to Slack.
 // Messages are constructed using BlockUI commands.
+// Messages sent by earlier events are updated if their content changes, and can be deleted with Delete.
+// Attach uploads a file to the same channel and thread after the message is first sent.
 type Message interface {
 	BlockUI
 	HasError
 
 	Channel(channelID string)
+	Delete()
+	Attach(name string, content io.Reader) HasError
 }
 
 type NonInteractiveMessage interface {
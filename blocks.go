@@ -1,5 +1,10 @@
 package spanner
 
+import (
+	"context"
+	"time"
+)
+
 // BlockUI allows the creation of Slack blocks in a message or modal.
 type BlockUI interface {
 	NonInteractiveBlockUI
@@ -13,14 +18,58 @@ type NonInteractiveBlockUI interface {
 }
 
 type InteractiveBlockUI interface {
-	TextInput(label string, hint string, placeholder string) string
-	MultilineTextInput(label string, hint string, placeholder string) string
+	TextInput(label string, hint string, placeholder string, opts ...ElementOption) string
+	MultilineTextInput(label string, hint string, placeholder string, opts ...ElementOption) string
 	Divider()
-	Select(title string, options []Option) string
-	MultipleSelect(title string, options []Option) []string
-	Button(label string) bool
+	Select(title string, options []Option, opts ...ElementOption) string
+	ExternalSelect(title string, loader OptionLoader, opts ...ElementOption) string
+	MultipleSelect(title string, options []Option, opts ...ElementOption) []string
+	Button(label string, opts ...ElementOption) bool
+	DatePicker(label string, hint string, opts ...ElementOption) time.Time
+	TimePicker(label string, hint string, opts ...ElementOption) time.Time
+	DateTimePicker(label string, hint string, opts ...ElementOption) time.Time
+	Checkboxes(title string, options []Option, opts ...ElementOption) []string
+	RadioButtons(title string, options []Option, opts ...ElementOption) string
+	UserSelect(title string, opts ...ElementOption) User
+	MultipleUserSelect(title string, opts ...ElementOption) []User
+	ChannelSelect(title string, opts ...ElementOption) Channel
+	MultipleChannelSelect(title string, opts ...ElementOption) []Channel
+	ConversationSelect(title string, filter ConversationFilter, opts ...ElementOption) Channel
+	MultipleConversationSelect(title string, filter ConversationFilter, opts ...ElementOption) []Channel
+}
+
+// ElementOption configures an interactive element.
+type ElementOption func(*ElementOptions)
+
+// ElementOptions holds the configuration for an interactive element.
+type ElementOptions struct {
+	ID      string
+	Default []string
 }
 
+// WithID sets a stable ID for an interactive element.
+// By default, elements are identified by their position, so adding or removing an element
+// will change the ID of every element after it. Elements with a stable ID keep their state
+// regardless of the elements around them.
+// IDs must be unique within a message or modal.
+func WithID(id string) ElementOption {
+	return func(o *ElementOptions) {
+		o.ID = id
+	}
+}
+
+// WithDefault sets the values of the options that are selected before the user
+// has interacted with checkboxes or radio buttons.
+func WithDefault(values ...string) ElementOption {
+	return func(o *ElementOptions) {
+		o.Default = values
+	}
+}
+
+// OptionLoader loads the options for an external select.
+// The query is the text the user has typed to filter the options.
+type OptionLoader func(ctx context.Context, query string) []Option
+
 // Option defines an option for select or checkbox blocks.
 type Option struct {
 	Label       string
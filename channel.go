@@ -2,7 +2,42 @@ package spanner
 
 import "context"
 
+// Channel represents a Slack channel or conversation.
+// Details of the channel are loaded from Slack when first needed. Load returns any error loading the channel,
+// if it cannot be loaded, Name will return an empty string.
+// To manage a channel, use the functions on Event such as SetChannelTopic and InviteToChannel.
 type Channel interface {
 	ID() string
 	Name(context.Context) string
+
+	// IsPrivate returns true if the channel is private.
+	IsPrivate(context.Context) bool
+	// IsIM returns true if the channel is a direct message conversation.
+	IsIM(context.Context) bool
+	// IsShared returns true if the channel is shared with other workspaces or organizations.
+	IsShared(context.Context) bool
+	Topic(context.Context) string
+	Purpose(context.Context) string
+	// Members returns the IDs of all users in the channel, this is requested from Slack for each event.
+	Members(context.Context) []string
+
+	Load(context.Context) error
+}
+
+// ChannelHandle refers to a channel that will be created when the event's actions are performed.
+// ID returns a placeholder that may be used as the channel ID for any later actions in the same event,
+// such as SendMessage or InviteToChannel. It is replaced with the ID of the new channel when those
+// actions are performed.
+type ChannelHandle interface {
+	HasError
+	ID() string
+}
+
+// ConversationFilter limits the conversations that are available in a conversation select.
+type ConversationFilter struct {
+	// Include lists the types of conversation to include: "im", "mpim", "private" or "public".
+	// If empty, all types are included.
+	Include                       []string
+	ExcludeExternalSharedChannels bool
+	ExcludeBotUsers               bool
 }
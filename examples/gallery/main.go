@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	spanner "github.com/theothertomelliott/spanner"
 	"github.com/theothertomelliott/spanner/slack"
@@ -62,8 +63,44 @@ func main() {
 				reply.PlainText(fmt.Sprintf("You chose: %v", letter))
 			}
 
+			fruit := reply.ExternalSelect("Pick a fruit", func(ctx context.Context, query string) []spanner.Option {
+				var out []spanner.Option
+				for _, fruit := range []string{"apple", "banana", "cherry", "grape", "orange", "pear"} {
+					if strings.Contains(fruit, query) {
+						out = append(out, spanner.Option{Label: fruit, Value: fruit})
+					}
+				}
+				return out
+			})
+
 			numbers := reply.MultipleSelect("Pick some numbers", spanner.Options("0", "1", "2", "3", "4", "5", "6", "7", "8", "9"))
 
+			reply.Divider()
+
+			reply.Header("User and channel selects")
+
+			person := reply.UserSelect("Pick a user")
+			channels := reply.MultipleChannelSelect("Pick some channels")
+			dm := reply.ConversationSelect("Pick a direct message", spanner.ConversationFilter{
+				Include:         []string{"im"},
+				ExcludeBotUsers: true,
+			})
+
+			reply.Divider()
+
+			reply.Header("Checkboxes and radio buttons")
+
+			colors := reply.Checkboxes("Pick some colors", spanner.Options("red", "green", "blue"), spanner.WithDefault("red"))
+			size := reply.RadioButtons("Pick a size", spanner.Options("small", "medium", "large"), spanner.WithDefault("medium"))
+
+			reply.Divider()
+
+			reply.Header("Date and time inputs")
+
+			date := reply.DatePicker("Date", "Pick a date")
+			timeOfDay := reply.TimePicker("Time", "Pick a time")
+			dateTime := reply.DateTimePicker("Date and time", "Pick a date and time")
+
 			if reply.Button("Done") {
 				summary := ev.SendMessage(msg.Channel().ID())
 				summary.PlainText("Here's a summary of what you entered")
@@ -71,7 +108,22 @@ func main() {
 				summary.PlainText(fmt.Sprintf("Single line: %q", singleLine))
 				summary.PlainText(fmt.Sprintf("Multi line: %q", multiLine))
 				summary.PlainText(fmt.Sprintf("You chose %q", letter))
+				summary.PlainText(fmt.Sprintf("Fruit: %q", fruit))
 				summary.PlainText(fmt.Sprintf("Numbers: %v", numbers))
+				if person != nil {
+					summary.PlainText(fmt.Sprintf("User: %v", person.RealName(ctx)))
+				}
+				for _, channel := range channels {
+					summary.PlainText(fmt.Sprintf("Channel: %v", channel.Name(ctx)))
+				}
+				if dm != nil {
+					summary.PlainText(fmt.Sprintf("Direct message: %v", dm.ID()))
+				}
+				summary.PlainText(fmt.Sprintf("Colors: %v", colors))
+				summary.PlainText(fmt.Sprintf("Size: %q", size))
+				summary.PlainText(fmt.Sprintf("Date: %v", date.Format("2006-01-02")))
+				summary.PlainText(fmt.Sprintf("Time: %v", timeOfDay.Format("15:04")))
+				summary.PlainText(fmt.Sprintf("Date and time: %v", dateTime))
 			}
 		}
 	})
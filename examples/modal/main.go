@@ -87,6 +87,13 @@ func handler(ctx context.Context, ev spanner.Event) {
 				fmt.Println("Multi line:", multiLine)
 
 				if submit := modal2.SubmitButton("Submit"); submit != nil {
+					if singleLine == "" {
+						submit.Reject(map[string]string{
+							"Single line": "Please enter some text",
+						})
+						return
+					}
+
 					msg := ev.SendMessage(testSlash.Channel().ID())
 					msg.Markdown(fmt.Sprintf("Thank you for completing our modal view <@%v>", testSlash.User().Name(ctx)))
 					msg.PlainText(fmt.Sprintf("Your number was %v", finalNumber))
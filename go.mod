@@ -2,6 +2,6 @@ module github.com/theothertomelliott/spanner
 
 go 1.21.2
 
-require github.com/slack-go/slack v0.12.1
+require github.com/slack-go/slack v0.12.3
 
 require github.com/gorilla/websocket v1.4.2 // indirect
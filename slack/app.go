@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"runtime/debug"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/socketmode"
@@ -18,10 +21,40 @@ type AppConfig struct {
 	AppToken string
 	Debug    bool
 
+	// SigningSecret is used to verify requests received by apps created with NewHTTPApp.
+	SigningSecret string
+	// ListenAddr is the address on which apps created with NewHTTPApp serve requests.
+	// Defaults to ":8080".
+	ListenAddr string
+
+	// StateStore stores event state between interactions.
+	// If not set, state is embedded in message metadata and modal private metadata.
+	StateStore StateStore
+
+	// MetadataCache caches details of users and channels between events.
+	// If not set, a cache is created with a TTL of DefaultMetadataCacheTTL.
+	MetadataCache *MetadataCache
+	// PrefetchMetadata loads all users and channels into the MetadataCache when the app starts.
+	// This requires the users:read, channels:read and groups:read scopes.
+	PrefetchMetadata bool
+
 	// AckOnError acknowledges messages when there is an error performing actions to prevent
 	// Slack from sending a retry. This will avoid actions being duplicated.
 	AckOnError bool
 
+	// Concurrency is the number of events that may be handled in parallel.
+	// Defaults to 1, which handles events one at a time.
+	Concurrency int
+	// Ordering determines which events are handled in the order they were received
+	// when Concurrency is greater than 1.
+	// Defaults to OrderByChannel.
+	Ordering Ordering
+	// EventBufferSize is the number of received events that may be waiting to be handled by each worker.
+	// Once a worker's buffer is full, receiving further events waits until the worker has space, so
+	// events are never dropped but a slow handler may delay events for other workers.
+	// Defaults to 2.
+	EventBufferSize int
+
 	EventInterceptor   spanner.EventInterceptor
 	HandlerInterceptor spanner.HandlerInterceptor
 	ActionInterceptor  spanner.ActionInterceptor
@@ -88,12 +121,30 @@ func newAppWithClient(client socketClient, config AppConfig, slackEvents chan so
 		}
 	}
 
+	if config.MetadataCache == nil {
+		config.MetadataCache = NewMetadataCache(DefaultMetadataCacheTTL)
+	}
+
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	if config.EventBufferSize < 1 {
+		config.EventBufferSize = 2
+	}
+
+	shutdowner, _ := client.(shutdownClient)
 	return &app{
-		config:        config,
-		client:        client,
+		config:     config,
+		shutdowner: shutdowner,
+		client: &cachingClient{
+			socketClient: client,
+			cache:        config.MetadataCache,
+		},
 		slackEvents:   slackEvents,
-		combinedEvent: make(chan combinedEvent, 2),
-		customEvents:  make(chan *customEvent, 2),
+		combinedEvent: make(chan combinedEvent, config.EventBufferSize),
+		customEvents:  make(chan *customEvent, config.EventBufferSize),
+		stopping:      make(chan struct{}),
+		stopped:       make(chan struct{}),
 	}
 }
 
@@ -101,22 +152,42 @@ type wrappedClient struct {
 	*socketmode.Client
 }
 
-func (w *wrappedClient) SendMessageWithMetadata(ctx context.Context, channelID string, blocks []slack.Block, metadata slack.SlackMetadata) (string, string, string, error) {
-	return w.SendMessageContext(ctx, channelID, slack.MsgOptionBlocks(blocks...), slack.MsgOptionMetadata(metadata))
+func (w *wrappedClient) SendMessageWithMetadata(ctx context.Context, channelID string, blocks []slack.Block, metadata slack.SlackMetadata, options ...slack.MsgOption) (string, string, string, error) {
+	options = append([]slack.MsgOption{slack.MsgOptionBlocks(blocks...), slack.MsgOptionMetadata(metadata)}, options...)
+	return w.SendMessageContext(ctx, channelID, options...)
 }
 
 func (w *wrappedClient) UpdateMessageWithMetadata(ctx context.Context, channelID string, timestamp string, blocks []slack.Block, metadata slack.SlackMetadata) (string, string, string, error) {
 	return w.UpdateMessageContext(ctx, channelID, timestamp, slack.MsgOptionBlocks(blocks...), slack.MsgOptionMetadata(metadata))
 }
 
+// shutdownClient is implemented by clients that serve requests from Slack themselves,
+// so they can stop accepting requests while in-flight events are finished.
+type shutdownClient interface {
+	Shutdown(ctx context.Context) error
+}
+
+// clientShutdownTimeout limits how long a shutdownClient may take to finish its requests
+// when the app is stopped by cancelling its context.
+const clientShutdownTimeout = 10 * time.Second
+
 type app struct {
-	client socketClient
+	client     socketClient
+	shutdowner shutdownClient
 
 	config AppConfig
 
 	slackEvents   chan socketmode.Event
 	customEvents  chan *customEvent
 	combinedEvent chan combinedEvent
+
+	runMtx      sync.Mutex
+	started     bool
+	shutdownCtx context.Context
+
+	stopOnce sync.Once
+	stopping chan struct{}
+	stopped  chan struct{}
 }
 
 type combinedEvent struct {
@@ -125,46 +196,244 @@ type combinedEvent struct {
 }
 
 func (s *app) Run(handler spanner.EventHandlerFunc) error {
+	return s.RunContext(context.Background(), handler)
+}
+
+// RunContext handles events until the context is cancelled or Shutdown is called.
+// When stopping, no new events are received and in-flight events are finished before returning.
+// An app may only be run once.
+func (s *app) RunContext(ctx context.Context, handler spanner.EventHandlerFunc) error {
+	s.runMtx.Lock()
+	if s.started {
+		s.runMtx.Unlock()
+		return fmt.Errorf("running app: app has already been run")
+	}
+	s.started = true
+	s.runMtx.Unlock()
+
+	defer close(s.stopped)
+	defer s.stop()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.stop()
+		case <-s.stopping:
+		}
+	}()
+
+	if s.config.PrefetchMetadata {
+		go func() {
+			if err := s.config.MetadataCache.prefetch(ctx, s.client); err != nil {
+				log.Printf("prefetching metadata: %v", err)
+			}
+		}()
+	}
+
 	go func() {
-		for ce := range s.customEvents {
-			s.combinedEvent <- combinedEvent{
-				customEvent: ce,
+		for {
+			select {
+			case ce := <-s.customEvents:
+				s.forward(combinedEvent{
+					customEvent: ce,
+				})
+			case <-s.stopping:
+				return
 			}
 		}
 	}()
 	go func() {
-		for evt := range s.slackEvents {
-			s.combinedEvent <- combinedEvent{
-				ev: &evt,
+		for {
+			select {
+			case evt, ok := <-s.slackEvents:
+				if !ok {
+					return
+				}
+				s.forward(combinedEvent{
+					ev: &evt,
+				})
+			case <-s.stopping:
+				return
 			}
 		}
 	}()
 
+	// The client is stopped separately so events can still be acknowledged
+	// while in-flight events are finishing.
+	clientCtx, cancelClient := context.WithCancel(context.Background())
+	defer cancelClient()
+
 	done := make(chan error)
 	go func() {
-		err := s.client.RunContext(context.Background())
-		if err != nil {
+		err := s.client.RunContext(clientCtx)
+		if err != nil && clientCtx.Err() == nil {
 			done <- err
 		}
 		close(done)
 	}()
 
-	for {
-		select {
-		case ce := <-s.combinedEvent:
-			ctx := context.Background()
-			if ce.customEvent != nil && ce.customEvent.ctx != nil {
-				ctx = ce.customEvent.ctx
+	// Each worker handles its events in order, so events with the same
+	// ordering key are always sent to the same worker.
+	var wg sync.WaitGroup
+	workers := make([]chan combinedEvent, s.config.Concurrency)
+	for i := range workers {
+		workers[i] = make(chan combinedEvent, s.config.EventBufferSize)
+		wg.Add(1)
+		go func(events chan combinedEvent) {
+			defer wg.Done()
+			for ce := range events {
+				s.processEvent(handler, ce)
 			}
+		}(workers[i])
+	}
 
-			process := func(ctx context.Context) {
-				s.handleEvent(ctx, handler, ce)
+	var next int
+	dispatch := func(ce combinedEvent) {
+		key := orderingKey(ce, s.config.Ordering)
+		if key == "" {
+			// Events without a key can go to any worker with space
+			for range workers {
+				select {
+				case workers[workerIndex(key, len(workers), &next)] <- ce:
+					return
+				default:
+				}
 			}
-			s.config.EventInterceptor(ctx, process)
-		case err := <-done:
-			return err
+		}
+
+		// Wait for the worker to have space, so events are never lost
+		select {
+		case workers[workerIndex(key, len(workers), &next)] <- ce:
+		case <-ctx.Done():
+			log.Printf("dropping event, app context is done: %v", ctx.Err())
 		}
 	}
+
+	var (
+		clientStopped bool
+		clientErr     error
+	)
+	for running := true; running; {
+		select {
+		case ce := <-s.combinedEvent:
+			dispatch(ce)
+		case clientErr = <-done:
+			clientStopped = true
+			s.stop()
+			running = false
+		case <-s.stopping:
+			running = false
+		}
+	}
+
+	// Stop accepting new requests while events that have already been accepted are finished
+	shutdownErr := make(chan error, 1)
+	if s.shutdowner != nil {
+		go func() {
+			shutdownErr <- s.shutdownClient()
+		}()
+	} else {
+		shutdownErr <- nil
+	}
+
+	// Finish events that have already been accepted
+	for drained := false; !drained; {
+		select {
+		case ce := <-s.combinedEvent:
+			dispatch(ce)
+		case ce := <-s.customEvents:
+			dispatch(combinedEvent{customEvent: ce})
+		default:
+			drained = true
+		}
+	}
+	for _, w := range workers {
+		close(w)
+	}
+	wg.Wait()
+
+	if clientStopped {
+		return clientErr
+	}
+	if err := <-shutdownErr; err != nil {
+		return err
+	}
+	cancelClient()
+	return <-done
+}
+
+// shutdownClient stops the client from accepting new requests and waits for those in flight to finish,
+// using the context passed to Shutdown, or a timeout if the app was stopped by cancelling its context.
+func (s *app) shutdownClient() error {
+	s.runMtx.Lock()
+	ctx := s.shutdownCtx
+	s.runMtx.Unlock()
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), clientShutdownTimeout)
+		defer cancel()
+	}
+	return s.shutdowner.Shutdown(ctx)
+}
+
+// forward sends an event to be handled, unless the app is stopping.
+// Slack events that are not handled will not be acknowledged, so Slack will retry them.
+func (s *app) forward(ce combinedEvent) {
+	select {
+	case s.combinedEvent <- ce:
+	case <-s.stopping:
+		if ce.customEvent != nil {
+			log.Printf("dropping custom event received during shutdown")
+		}
+	}
+}
+
+// Shutdown stops the app from receiving new events and waits for in-flight events to finish.
+// If the context is done before all events have finished, the context's error is returned.
+// An error is returned immediately if the app is not running.
+func (s *app) Shutdown(ctx context.Context) error {
+	s.runMtx.Lock()
+	started := s.started
+	if started && s.shutdownCtx == nil {
+		s.shutdownCtx = ctx
+	}
+	s.runMtx.Unlock()
+	if !started {
+		return fmt.Errorf("shutting down app: app is not running")
+	}
+
+	s.stop()
+
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *app) stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopping)
+	})
+}
+
+func (s *app) processEvent(handler spanner.EventHandlerFunc, ce combinedEvent) {
+	ctx := context.Background()
+	if ce.customEvent != nil && ce.customEvent.ctx != nil {
+		ctx = ce.customEvent.ctx
+	}
+
+	// Recover from panics in the interceptor, so a single bad event doesn't stop the app
+	defer s.recoverEvent(ce)
+
+	s.config.MetadataCache.update(ce.ev)
+
+	process := func(ctx context.Context) {
+		s.handleEvent(ctx, handler, ce)
+	}
+	s.config.EventInterceptor(ctx, process)
 }
 
 func (s *app) handleEvent(ctx context.Context, handler spanner.EventHandlerFunc, ce combinedEvent) {
@@ -177,13 +446,20 @@ func (s *app) handleEvent(ctx context.Context, handler spanner.EventHandlerFunc,
 		hasReq = true
 	}
 
-	es := parseCombinedEvent(ctx, s.client, ce)
+	// Recover from panics in the handler or while processing the event here,
+	// so the interceptor can finish normally
+	defer s.recoverEvent(ce)
+
+	es := parseCombinedEvent(ctx, s.client, s.config.StateStore, ce)
 
 	doHandle := func(ctx context.Context) {
 		handler(ctx, es)
 	}
 
-	s.config.HandlerInterceptor(ctx, es.eventType, doHandle)
+	// Interactions whose state could not be restored are only acknowledged
+	if es.eventType != "stale_interaction" {
+		s.config.HandlerInterceptor(ctx, es.eventType, doHandle)
+	}
 
 	var finishFunc = func(ctx context.Context) error {
 		return es.finishEvent(ctx, s.config.ActionInterceptor, request{
@@ -191,10 +467,11 @@ func (s *app) handleEvent(ctx context.Context, handler spanner.EventHandlerFunc,
 			es:     es,
 			hash:   es.hash,
 			client: s.client,
+			store:  s.config.StateStore,
 		})
 	}
 
-	err := s.config.FinishInterceptor(ctx, es.state.actionQueue.Actions(), finishFunc)
+	err := s.config.FinishInterceptor(ctx, es.pendingActions().Actions(), finishFunc)
 	if err != nil {
 		log.Printf("handling request: %v", renderSlackError(err))
 		if s.config.AckOnError && hasReq {
@@ -205,12 +482,37 @@ func (s *app) handleEvent(ctx context.Context, handler spanner.EventHandlerFunc,
 	}
 }
 
+// recoverEvent recovers from a panic while handling an event, logging it and acknowledging
+// the event so Slack will not retry it.
+func (s *app) recoverEvent(ce combinedEvent) {
+	if r := recover(); r != nil {
+		log.Printf("handling request: recovered from panic: %v\n%s", r, debug.Stack())
+		if evt := ce.ev; evt != nil && evt.Request != nil {
+			s.client.Ack(*evt.Request, map[string]interface{}{})
+		}
+	}
+}
+
+// SendCustom queues a custom event to be handled.
+// If the event buffer is full, this will block until there is space or the context is done.
 func (s *app) SendCustom(ctx context.Context, c spanner.CustomEvent) error {
-	s.customEvents <- &customEvent{
+	select {
+	case <-s.stopping:
+		return fmt.Errorf("sending custom event: app is shutting down")
+	default:
+	}
+
+	select {
+	case s.customEvents <- &customEvent{
 		ctx:  ctx,
 		body: c.Body(),
+	}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("sending custom event: %w", ctx.Err())
+	case <-s.stopping:
+		return fmt.Errorf("sending custom event: app is shutting down")
 	}
-	return nil
 }
 
 type request struct {
@@ -219,12 +521,31 @@ type request struct {
 	hash string
 
 	client socketClient
+	store  StateStore
 }
 
-func (r request) Metadata() []byte {
+// channelID resolves the placeholder ID of a channel created by an earlier action for this event.
+// The IDs of existing channels are returned unchanged.
+func (r request) channelID(id string) string {
+	if created, ok := r.es.state.CreatedChannels[id]; ok {
+		return created
+	}
+	return id
+}
+
+// Metadata returns the event state to be sent to Slack.
+// If a state store is configured, the state is saved to the store and a reference is returned.
+// All references for the same event share a key, so saving the state again updates them all.
+func (r request) Metadata(ctx context.Context) ([]byte, error) {
 	metadata, err := json.Marshal(r.es.state)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("encoding state: %w", err)
+	}
+	if r.store != nil && r.es.stateKey == "" {
+		r.es.stateKey, err = newStateKey()
+		if err != nil {
+			return nil, err
+		}
 	}
-	return metadata
+	return saveState(ctx, r.store, r.es.stateKey, metadata)
 }
@@ -2,9 +2,12 @@ package slack
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
 	"github.com/theothertomelliott/spanner"
 )
@@ -41,3 +44,265 @@ func TestHandlerIsCalledForEachEvent(t *testing.T) {
 		t.Errorf("expected run to be called exactly once")
 	}
 }
+
+func TestConcurrentEventsAreOrderedByChannel(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := newAppWithClient(
+		client,
+		AppConfig{
+			Concurrency: 2,
+			Ordering:    OrderByChannel,
+		},
+		client.Events,
+	)
+
+	// Find two channels that will be handled by different workers
+	var next int
+	slowChannel, fastChannel := "C0", "C1"
+	for i := 2; workerIndex(slowChannel, 2, &next) == workerIndex(fastChannel, 2, &next); i++ {
+		fastChannel = fmt.Sprintf("C%d", i)
+	}
+
+	release := make(chan struct{})
+	handled := make(chan string, 3)
+
+	go func() {
+		testApp.Run(func(ctx context.Context, evt spanner.Event) {
+			msg := evt.ReceiveMessage()
+			if msg == nil {
+				return
+			}
+			if msg.Text() == "slow" {
+				<-release
+			}
+			handled <- msg.Text()
+		})
+	}()
+
+	client.SendEventToAppAsync(messageEvent(slackevents.MessageEvent{Channel: slowChannel, Text: "slow"}))
+	time.Sleep(10 * time.Millisecond)
+	client.SendEventToAppAsync(messageEvent(slackevents.MessageEvent{Channel: slowChannel, Text: "after slow"}))
+	time.Sleep(10 * time.Millisecond)
+	client.SendEventToAppAsync(messageEvent(slackevents.MessageEvent{Channel: fastChannel, Text: "fast"}))
+
+	select {
+	case text := <-handled:
+		if text != "fast" {
+			t.Errorf("expected the fast event to be handled first, got %q", text)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timeout waiting for fast event")
+	}
+
+	close(release)
+	for _, expected := range []string{"slow", "after slow"} {
+		select {
+		case text := <-handled:
+			if text != expected {
+				t.Errorf("expected %q, got %q", expected, text)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for %q", expected)
+		}
+	}
+}
+
+func TestSlowWorkerAppliesBackpressure(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := newAppWithClient(client, AppConfig{}, client.Events)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handled := make(chan string, 6)
+
+	go func() {
+		testApp.Run(func(ctx context.Context, evt spanner.Event) {
+			msg := evt.ReceiveMessage()
+			if msg == nil {
+				return
+			}
+			if msg.Text() == "0" {
+				close(started)
+				<-release
+			}
+			handled <- msg.Text()
+		})
+	}()
+
+	// Send more events than can be buffered while the handler is blocked
+	go func() {
+		for i := 0; i < 6; i++ {
+			client.Events <- messageEvent(slackevents.MessageEvent{Channel: "ABC123", Text: fmt.Sprint(i)})
+			if i == 0 {
+				<-started
+			}
+		}
+	}()
+
+	<-started
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < 6; i++ {
+		select {
+		case text := <-handled:
+			if expected := fmt.Sprint(i); text != expected {
+				t.Errorf("expected %q, got %q", expected, text)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for event %d", i)
+		}
+	}
+}
+
+// failingClient is a test client whose connection fails when an error is sent on fail.
+type failingClient struct {
+	*testClient
+	fail chan error
+}
+
+func (f *failingClient) RunContext(ctx context.Context) error {
+	return <-f.fail
+}
+
+func TestClientErrorFinishesInFlightEvents(t *testing.T) {
+	client := &failingClient{
+		testClient: newTestClient([]string{"ABC123"}),
+		fail:       make(chan error),
+	}
+	testApp := newAppWithClient(client, AppConfig{}, client.Events)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished bool
+
+	runErr := make(chan error)
+	go func() {
+		runErr <- testApp.Run(func(ctx context.Context, evt spanner.Event) {
+			if msg := evt.ReceiveMessage(); msg != nil {
+				close(started)
+				<-release
+				finished = true
+			}
+		})
+	}()
+
+	client.Events <- messageEvent(slackevents.MessageEvent{Channel: "ABC123", Text: "hello"})
+	<-started
+
+	client.fail <- errors.New("connection lost")
+
+	select {
+	case <-runErr:
+		t.Fatalf("run returned before in-flight event finished")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-runErr:
+		if err == nil || err.Error() != "connection lost" {
+			t.Errorf("expected the client error to be returned, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timeout waiting for run to return")
+	}
+	if !finished {
+		t.Errorf("expected in-flight event to finish")
+	}
+}
+
+func TestSendCustomRespectsContext(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := newAppWithClient(client, AppConfig{EventBufferSize: 1}, client.Events)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// The app isn't running, so the buffer will fill up
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = testApp.SendCustom(ctx, NewCustomEvent(map[string]interface{}{}))
+	}
+	if err == nil {
+		t.Errorf("expected an error once the event buffer was full")
+	}
+}
+
+func TestShutdownFinishesInFlightEvents(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished bool
+
+	runErr := make(chan error)
+	go func() {
+		runErr <- testApp.Run(func(ctx context.Context, evt spanner.Event) {
+			if msg := evt.ReceiveMessage(); msg != nil {
+				close(started)
+				<-release
+				evt.SendMessage(msg.Channel().ID()).PlainText("done")
+				finished = true
+			}
+		})
+	}()
+
+	client.SendEventToAppAsync(messageEvent(slackevents.MessageEvent{Channel: "ABC123", Text: "hello"}))
+	<-started
+
+	shutdownErr := make(chan error)
+	go func() {
+		shutdownErr <- testApp.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-shutdownErr:
+		t.Fatalf("shutdown returned before in-flight event finished")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdownErr; err != nil {
+		t.Errorf("unexpected error shutting down: %v", err)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("unexpected error from run: %v", err)
+	}
+
+	if !finished {
+		t.Errorf("expected in-flight event to finish")
+	}
+	if len(client.messagesSent) != 1 {
+		t.Errorf("expected one message to be sent, got %d", len(client.messagesSent))
+	}
+	if len(client.acked) != 1 {
+		t.Errorf("expected event to be acknowledged, got %d acks", len(client.acked))
+	}
+	if err := testApp.SendCustom(context.Background(), NewCustomEvent(nil)); err == nil {
+		t.Errorf("expected an error sending a custom event after shutdown")
+	}
+}
+
+func TestRunAndShutdownOnlyWhileRunning(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	if err := testApp.Shutdown(context.Background()); err == nil {
+		t.Errorf("expected an error shutting down an app that isn't running")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := testApp.RunContext(ctx, func(ctx context.Context, evt spanner.Event) {}); err != nil {
+		t.Errorf("unexpected error from run: %v", err)
+	}
+
+	if err := testApp.Run(func(ctx context.Context, evt spanner.Event) {}); err == nil {
+		t.Errorf("expected an error running an app a second time")
+	}
+	if err := testApp.Shutdown(context.Background()); err != nil {
+		t.Errorf("unexpected error shutting down a stopped app: %v", err)
+	}
+}
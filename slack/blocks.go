@@ -3,8 +3,10 @@ package slack
 import (
 	"crypto/sha1"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/theothertomelliott/spanner"
@@ -13,9 +15,23 @@ import (
 var _ spanner.BlockUI = &Blocks{}
 
 type Blocks struct {
+	client socketClient
+	sender *MessageSender
+
 	blocks      []slack.Block
 	BlockStates map[string]BlockState `json:"block_state,omitempty"`
 	inputID     int
+
+	// BlockIDs records the IDs of interactive elements identified by their position, keyed by label,
+	// so changes between renders can be detected.
+	BlockIDs    map[string]string `json:"block_ids,omitempty"`
+	previousIDs map[string]string
+	rendering   bool
+	elementIDs  map[string]string
+	labels      map[string]string
+	labelCounts map[string]int
+
+	suggestion *blockSuggestion
 }
 
 type BlockState struct {
@@ -53,10 +69,37 @@ func blockActionToState(p eventPopulation) map[string]BlockState {
 			for _, option := range action.SelectedOptions {
 				state.StringSlice = append(state.StringSlice, option.Value)
 			}
+			state.StringSlice = append(state.StringSlice, action.SelectedUsers...)
+			state.StringSlice = append(state.StringSlice, action.SelectedChannels...)
+			state.StringSlice = append(state.StringSlice, action.SelectedConversations...)
+			if action.SelectedUser != "" {
+				state.String = action.SelectedUser
+				continue
+			}
+			if action.SelectedChannel != "" {
+				state.String = action.SelectedChannel
+				continue
+			}
+			if action.SelectedConversation != "" {
+				state.String = action.SelectedConversation
+				continue
+			}
 			if action.SelectedOption.Value != "" {
 				state.String = action.SelectedOption.Value
 				continue
 			}
+			if action.SelectedDate != "" {
+				state.String = action.SelectedDate
+				continue
+			}
+			if action.SelectedTime != "" {
+				state.String = action.SelectedTime
+				continue
+			}
+			if action.SelectedDateTime != 0 {
+				state.Int = int(action.SelectedDateTime)
+				continue
+			}
 			if action.Value != "" {
 				state.String = action.Value
 				continue
@@ -119,8 +162,8 @@ func (b *Blocks) Divider() {
 	b.blocks = append(b.blocks, slack.NewDividerBlock())
 }
 
-func (b *Blocks) TextInput(label, hint, placeholder string) string {
-	inputBlockID, _ := b.addTextInput(label, hint, placeholder, false)
+func (b *Blocks) TextInput(label, hint, placeholder string, opts ...spanner.ElementOption) string {
+	inputBlockID, _ := b.addTextInput(label, hint, placeholder, false, opts)
 
 	// Retrieve the text from the state
 	if state := b.state(); state != nil {
@@ -133,8 +176,8 @@ func (b *Blocks) TextInput(label, hint, placeholder string) string {
 	return ""
 }
 
-func (b *Blocks) MultilineTextInput(label, hint, placeholder string) string {
-	inputBlockID, _ := b.addTextInput(label, hint, placeholder, true)
+func (b *Blocks) MultilineTextInput(label, hint, placeholder string, opts ...spanner.ElementOption) string {
+	inputBlockID, _ := b.addTextInput(label, hint, placeholder, true, opts)
 
 	// Retrieve the text from the state
 	if state := b.state(); state != nil {
@@ -147,13 +190,9 @@ func (b *Blocks) MultilineTextInput(label, hint, placeholder string) string {
 	return ""
 }
 
-func (b *Blocks) addTextInput(label, hint, placeholder string, multiline bool) (string, string) {
-	defer func() {
-		b.inputID++
-	}()
-
-	inputBlockID := fmt.Sprintf("input-%v", b.inputID)
-	inputActionID := fmt.Sprintf("input%vaction", b.inputID)
+func (b *Blocks) addTextInput(label, hint, placeholder string, multiline bool, opts []spanner.ElementOption) (string, string) {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(label, inputBlockID, inputBlockID, positional)
 
 	textInput := slack.NewPlainTextInputBlockElement(
 		slack.NewTextBlockObject(slack.PlainTextType, placeholder, false, false),
@@ -176,8 +215,8 @@ func (b *Blocks) addTextInput(label, hint, placeholder string, multiline bool) (
 	return inputBlockID, inputActionID
 }
 
-func (b *Blocks) Select(title string, options []spanner.Option) string {
-	inputBlockID := b.addSelect(title, options)
+func (b *Blocks) Select(title string, options []spanner.Option, opts ...spanner.ElementOption) string {
+	inputBlockID := b.addSelect(title, options, opts)
 
 	// Retrieve the selected option from the state
 	if state := b.state(); state != nil {
@@ -190,43 +229,79 @@ func (b *Blocks) Select(title string, options []spanner.Option) string {
 	return ""
 }
 
-func (b *Blocks) addSelect(text string, options []spanner.Option) (inputBlockID string) {
-	defer func() {
-		b.inputID++
-	}()
+func (b *Blocks) addSelect(text string, options []spanner.Option, opts []spanner.ElementOption) (inputBlockID string) {
+	inputBlockID, inputActionID := b.optionsElementID(text, options, opts)
+
+	b.addOptionsInput(
+		inputBlockID,
+		text,
+		slack.NewOptionsSelectBlockElement(
+			slack.OptTypeStatic,
+			slack.NewTextBlockObject(slack.PlainTextType, text, false, false),
+			inputActionID,
+			optionBlockObjects(options)...,
+		),
+	)
+
+	return inputBlockID
+}
 
+// ExternalSelect adds a select with options provided by the loader as the user types.
+// The loader is called during the Finishing phase of a separate event when Slack
+// requests options.
+func (b *Blocks) ExternalSelect(title string, loader spanner.OptionLoader, opts ...spanner.ElementOption) string {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(title, inputBlockID, inputBlockID, positional)
+
+	if b.suggestion != nil && b.suggestion.blockID == inputBlockID {
+		b.suggestion.loader = loader
+	}
+
+	element := slack.NewOptionsSelectBlockElement(
+		slack.OptTypeExternal,
+		slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		inputActionID,
+	)
+	minQueryLength := 0
+	element.MinQueryLength = &minQueryLength
+	b.addOptionsInput(inputBlockID, title, element)
+
+	return b.state()[inputBlockID].String
+}
+
+func (b *Blocks) MultipleSelect(title string, options []spanner.Option, opts ...spanner.ElementOption) []string {
+	inputBlockID := b.addMultipleSelect(title, options, opts)
+
+	// Retrieve the selected option from the state
+	if state := b.state(); state != nil {
+		viewState := state
+		if state, ok := viewState[inputBlockID]; ok {
+			return state.StringSlice
+		}
+	}
+
+	return nil
+}
+
+func (b *Blocks) addMultipleSelect(text string, options []spanner.Option, opts []spanner.ElementOption) (inputBlockID string) {
 	var values []string
 	for _, option := range options {
 		values = append(values, option.Value)
 	}
-	sort.Strings(values)
 	optionHash := hashstr(strings.Join(values, ","))
 
-	inputBlockID = fmt.Sprintf("input-%v-%v", b.inputID, optionHash)
-	inputActionID := fmt.Sprintf("input%vaction", b.inputID)
+	elementID, inputActionID, positional := b.elementID(opts)
+	inputBlockID = fmt.Sprintf("%v-%v", elementID, optionHash)
+	b.trackID(text, elementID, inputBlockID, positional)
 
-	var optionObjects []*slack.OptionBlockObject
-	for _, option := range options {
-		var description *slack.TextBlockObject
-		if option.Description != "" {
-			description = slack.NewTextBlockObject(slack.PlainTextType, option.Description, false, false)
-		}
-		optionObjects = append(
-			optionObjects,
-			slack.NewOptionBlockObject(
-				option.Value,
-				slack.NewTextBlockObject(slack.PlainTextType, option.Label, false, false),
-				description,
-			),
-		)
-	}
+	optionObjects := optionBlockObjects(options)
 
 	input := slack.NewInputBlock(
 		inputBlockID,
 		slack.NewTextBlockObject(slack.PlainTextType, text, false, false),
 		nil,
-		slack.NewOptionsSelectBlockElement(
-			slack.OptTypeStatic,
+		slack.NewOptionsMultiSelectBlockElement(
+			slack.MultiOptTypeStatic,
 			slack.NewTextBlockObject(slack.PlainTextType, text, false, false),
 			inputActionID,
 			optionObjects...,
@@ -241,77 +316,325 @@ func (b *Blocks) addSelect(text string, options []spanner.Option) (inputBlockID
 	return inputBlockID
 }
 
-func (b *Blocks) MultipleSelect(title string, options []spanner.Option) []string {
-	inputBlockID := b.addMultipleSelect(title, options)
+func (b *Blocks) Checkboxes(title string, options []spanner.Option, opts ...spanner.ElementOption) []string {
+	inputBlockID, inputActionID := b.optionsElementID(title, options, opts)
 
-	// Retrieve the selected option from the state
-	if state := b.state(); state != nil {
-		viewState := state
-		if state, ok := viewState[inputBlockID]; ok {
-			return state.StringSlice
+	// Use the default selection until the user has interacted with the checkboxes
+	selected := elementOptions(opts).Default
+	if state, ok := b.state()[inputBlockID]; ok {
+		selected = state.StringSlice
+	}
+
+	optionObjects := optionBlockObjects(options)
+	checkboxes := slack.NewCheckboxGroupsBlockElement(inputActionID, optionObjects...)
+	for _, option := range optionObjects {
+		for _, value := range selected {
+			if option.Value == value {
+				checkboxes.InitialOptions = append(checkboxes.InitialOptions, option)
+			}
 		}
 	}
 
-	return nil
+	b.addOptionsInput(inputBlockID, title, checkboxes)
+
+	return selected
 }
 
-func (b *Blocks) addMultipleSelect(text string, options []spanner.Option) (inputBlockID string) {
-	defer func() {
-		b.inputID++
-	}()
+func (b *Blocks) RadioButtons(title string, options []spanner.Option, opts ...spanner.ElementOption) string {
+	inputBlockID, inputActionID := b.optionsElementID(title, options, opts)
+
+	// Use the default selection until the user has interacted with the radio buttons
+	var selected string
+	if defaults := elementOptions(opts).Default; len(defaults) > 0 {
+		selected = defaults[0]
+	}
+	if state, ok := b.state()[inputBlockID]; ok {
+		selected = state.String
+	}
+
+	optionObjects := optionBlockObjects(options)
+	radioButtons := slack.NewRadioButtonsBlockElement(inputActionID, optionObjects...)
+	for _, option := range optionObjects {
+		if option.Value == selected {
+			radioButtons.InitialOption = option
+		}
+	}
+
+	b.addOptionsInput(inputBlockID, title, radioButtons)
+
+	return selected
+}
+
+func (b *Blocks) UserSelect(title string, opts ...spanner.ElementOption) spanner.User {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(title, inputBlockID, inputBlockID, positional)
 
+	state := b.state()[inputBlockID]
+
+	element := slack.NewOptionsSelectBlockElement(
+		slack.OptTypeUser,
+		slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		inputActionID,
+	)
+	element.InitialUser = state.String
+	b.addOptionsInput(inputBlockID, title, element)
+
+	if state.String == "" {
+		return nil
+	}
+	return b.user(state.String)
+}
+
+func (b *Blocks) MultipleUserSelect(title string, opts ...spanner.ElementOption) []spanner.User {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(title, inputBlockID, inputBlockID, positional)
+
+	state := b.state()[inputBlockID]
+
+	element := slack.NewOptionsMultiSelectBlockElement(
+		slack.MultiOptTypeUser,
+		slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		inputActionID,
+	)
+	element.InitialUsers = state.StringSlice
+	b.addOptionsInput(inputBlockID, title, element)
+
+	var users []spanner.User
+	for _, userID := range state.StringSlice {
+		users = append(users, b.user(userID))
+	}
+	return users
+}
+
+func (b *Blocks) ChannelSelect(title string, opts ...spanner.ElementOption) spanner.Channel {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(title, inputBlockID, inputBlockID, positional)
+
+	state := b.state()[inputBlockID]
+
+	element := slack.NewOptionsSelectBlockElement(
+		slack.OptTypeChannels,
+		slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		inputActionID,
+	)
+	element.InitialChannel = state.String
+	b.addOptionsInput(inputBlockID, title, element)
+
+	if state.String == "" {
+		return nil
+	}
+	return b.channel(state.String)
+}
+
+func (b *Blocks) MultipleChannelSelect(title string, opts ...spanner.ElementOption) []spanner.Channel {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(title, inputBlockID, inputBlockID, positional)
+
+	state := b.state()[inputBlockID]
+
+	element := slack.NewOptionsMultiSelectBlockElement(
+		slack.MultiOptTypeChannels,
+		slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		inputActionID,
+	)
+	element.InitialChannels = state.StringSlice
+	b.addOptionsInput(inputBlockID, title, element)
+
+	var channels []spanner.Channel
+	for _, channelID := range state.StringSlice {
+		channels = append(channels, b.channel(channelID))
+	}
+	return channels
+}
+
+func (b *Blocks) ConversationSelect(title string, filter spanner.ConversationFilter, opts ...spanner.ElementOption) spanner.Channel {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(title, inputBlockID, inputBlockID, positional)
+
+	state := b.state()[inputBlockID]
+
+	element := slack.NewOptionsSelectBlockElement(
+		slack.OptTypeConversations,
+		slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		inputActionID,
+	)
+	element.InitialConversation = state.String
+	element.Filter = conversationFilter(filter)
+	b.addOptionsInput(inputBlockID, title, element)
+
+	if state.String == "" {
+		return nil
+	}
+	return b.channel(state.String)
+}
+
+func (b *Blocks) MultipleConversationSelect(title string, filter spanner.ConversationFilter, opts ...spanner.ElementOption) []spanner.Channel {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(title, inputBlockID, inputBlockID, positional)
+
+	state := b.state()[inputBlockID]
+
+	element := slack.NewOptionsMultiSelectBlockElement(
+		slack.MultiOptTypeConversations,
+		slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		inputActionID,
+	)
+	element.InitialConversations = state.StringSlice
+	b.addOptionsInput(inputBlockID, title, &filteredMultiSelectBlockElement{
+		MultiSelectBlockElement: element,
+		Filter:                  conversationFilter(filter),
+	})
+
+	var channels []spanner.Channel
+	for _, channelID := range state.StringSlice {
+		channels = append(channels, b.channel(channelID))
+	}
+	return channels
+}
+
+// filteredMultiSelectBlockElement adds a conversation filter to a multi-select element,
+// which is not supported by slack.MultiSelectBlockElement.
+type filteredMultiSelectBlockElement struct {
+	*slack.MultiSelectBlockElement
+	Filter *slack.SelectBlockElementFilter `json:"filter,omitempty"`
+}
+
+func conversationFilter(filter spanner.ConversationFilter) *slack.SelectBlockElementFilter {
+	if len(filter.Include) == 0 && !filter.ExcludeExternalSharedChannels && !filter.ExcludeBotUsers {
+		return nil
+	}
+	return &slack.SelectBlockElementFilter{
+		Include:                       filter.Include,
+		ExcludeExternalSharedChannels: filter.ExcludeExternalSharedChannels,
+		ExcludeBotUsers:               filter.ExcludeBotUsers,
+	}
+}
+
+func (b *Blocks) user(userID string) *user {
+	return &user{
+		client:     b.client,
+		sender:     b.sender,
+		IDInternal: userID,
+	}
+}
+
+func (b *Blocks) channel(channelID string) *channel {
+	return &channel{
+		client:     b.client,
+		IDInternal: channelID,
+	}
+}
+
+// optionsElementID returns the block and action IDs for an element with a set of options.
+// The block ID includes a hash of the option values, so state is reset if the options change.
+func (b *Blocks) optionsElementID(title string, options []spanner.Option, opts []spanner.ElementOption) (string, string) {
 	var values []string
 	for _, option := range options {
 		values = append(values, option.Value)
 	}
+	sort.Strings(values)
 	optionHash := hashstr(strings.Join(values, ","))
 
-	inputBlockID = fmt.Sprintf("input-%v-%v", b.inputID, optionHash)
-	inputActionID := fmt.Sprintf("input%vaction", b.inputID)
+	elementID, inputActionID, positional := b.elementID(opts)
+	inputBlockID := fmt.Sprintf("%v-%v", elementID, optionHash)
+	b.trackID(title, elementID, inputBlockID, positional)
 
-	var optionObjects []*slack.OptionBlockObject
-	for _, option := range options {
-		var description *slack.TextBlockObject
-		if option.Description != "" {
-			description = slack.NewTextBlockObject(slack.PlainTextType, option.Description, false, false)
-		}
-		optionObjects = append(
-			optionObjects,
-			slack.NewOptionBlockObject(
-				option.Value,
-				slack.NewTextBlockObject(slack.PlainTextType, option.Label, false, false),
-				description,
-			),
-		)
-	}
+	return inputBlockID, inputActionID
+}
 
+func (b *Blocks) addOptionsInput(inputBlockID, title string, element slack.BlockElement) {
 	input := slack.NewInputBlock(
 		inputBlockID,
-		slack.NewTextBlockObject(slack.PlainTextType, text, false, false),
+		slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
 		nil,
-		slack.NewOptionsMultiSelectBlockElement(
-			slack.MultiOptTypeStatic,
-			slack.NewTextBlockObject(slack.PlainTextType, text, false, false),
-			inputActionID,
-			optionObjects...,
-		),
+		element,
 	)
 	input.DispatchAction = true
 
 	b.blocks = append(b.blocks,
 		input,
 	)
+}
 
-	return inputBlockID
+// dateFormat and timeFormat are the formats used by Slack for date and time picker values.
+const (
+	dateFormat = "2006-01-02"
+	timeFormat = "15:04"
+)
+
+// DatePicker adds a date picker and returns the selected date in UTC,
+// or the zero time if no date has been selected.
+func (b *Blocks) DatePicker(label string, hint string, opts ...spanner.ElementOption) time.Time {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(label, inputBlockID, inputBlockID, positional)
+
+	b.addPickerInput(inputBlockID, label, hint, slack.NewDatePickerBlockElement(inputActionID))
+
+	if state, ok := b.state()[inputBlockID]; ok && state.String != "" {
+		date, err := time.Parse(dateFormat, state.String)
+		if err == nil {
+			return date
+		}
+	}
+
+	return time.Time{}
 }
 
-func (b *Blocks) Button(label string) bool {
-	defer func() {
-		b.inputID++
-	}()
+// TimePicker adds a time picker and returns the selected time of day on
+// January 1st of year 0 in UTC, or the zero time if no time has been selected.
+func (b *Blocks) TimePicker(label string, hint string, opts ...spanner.ElementOption) time.Time {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(label, inputBlockID, inputBlockID, positional)
 
-	inputBlockID := fmt.Sprintf("input-%v", b.inputID)
-	inputActionID := fmt.Sprintf("input%vaction", b.inputID)
+	b.addPickerInput(inputBlockID, label, hint, slack.NewTimePickerBlockElement(inputActionID))
+
+	if state, ok := b.state()[inputBlockID]; ok && state.String != "" {
+		t, err := time.Parse(timeFormat, state.String)
+		if err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// DateTimePicker adds a date and time picker and returns the selected time,
+// or the zero time if no time has been selected.
+func (b *Blocks) DateTimePicker(label string, hint string, opts ...spanner.ElementOption) time.Time {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(label, inputBlockID, inputBlockID, positional)
+
+	b.addPickerInput(inputBlockID, label, hint, slack.NewDateTimePickerBlockElement(inputActionID))
+
+	if state, ok := b.state()[inputBlockID]; ok && state.Int != 0 {
+		return time.Unix(int64(state.Int), 0)
+	}
+
+	return time.Time{}
+}
+
+func (b *Blocks) addPickerInput(inputBlockID, label, hint string, element slack.BlockElement) {
+	var hintText *slack.TextBlockObject
+	if hint != "" {
+		hintText = slack.NewTextBlockObject(slack.PlainTextType, hint, false, false)
+	}
+
+	input := slack.NewInputBlock(
+		inputBlockID,
+		slack.NewTextBlockObject(slack.PlainTextType, label, false, false),
+		hintText,
+		element,
+	)
+	input.DispatchAction = true
+
+	b.blocks = append(b.blocks,
+		input,
+	)
+}
+
+func (b *Blocks) Button(label string, opts ...spanner.ElementOption) bool {
+	inputBlockID, inputActionID, positional := b.elementID(opts)
+	b.trackID(label, inputBlockID, inputBlockID, positional)
 
 	buttonInput := slack.NewButtonBlockElement(
 		inputActionID,
@@ -335,6 +658,105 @@ func (b *Blocks) Button(label string) bool {
 	return false
 }
 
+func elementOptions(opts []spanner.ElementOption) spanner.ElementOptions {
+	var options spanner.ElementOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// elementID returns the block and action IDs for an interactive element.
+// Elements with an ID set using spanner.WithID use that ID, otherwise the
+// ID is generated from the position of the element, and positional is true.
+func (b *Blocks) elementID(opts []spanner.ElementOption) (blockID string, actionID string, positional bool) {
+	options := elementOptions(opts)
+	if options.ID != "" {
+		return options.ID, fmt.Sprintf("%vaction", options.ID), false
+	}
+
+	defer func() {
+		b.inputID++
+	}()
+	return fmt.Sprintf("input-%v", b.inputID), fmt.Sprintf("input%vaction", b.inputID), true
+}
+
+// trackID records the block ID of an interactive element, and logs a warning if
+// an element identified by its position had a different ID when previously rendered,
+// or if an ID set using spanner.WithID is used by more than one element.
+// The element can later be found by its label or element ID with fieldBlockID.
+func (b *Blocks) trackID(label string, elementID string, blockID string, positional bool) {
+	if !b.rendering {
+		b.previousIDs = b.BlockIDs
+		b.BlockIDs = make(map[string]string)
+		b.elementIDs = make(map[string]string)
+		b.labels = make(map[string]string)
+		b.labelCounts = make(map[string]int)
+		b.rendering = true
+	}
+
+	if _, exists := b.elementIDs[elementID]; exists && !positional {
+		log.Printf(
+			"element %q has ID %q, which is already used by another element, use a unique ID with spanner.WithID",
+			label,
+			elementID,
+		)
+	}
+	b.elementIDs[elementID] = blockID
+	b.labels[label] = blockID
+
+	// Elements with an ID set using spanner.WithID are stable
+	if !positional {
+		return
+	}
+
+	// Distinguish elements that share a label by the order they were rendered
+	key := label
+	b.labelCounts[label]++
+	if count := b.labelCounts[label]; count > 1 {
+		key = fmt.Sprintf("%v#%v", label, count)
+	}
+	b.BlockIDs[key] = blockID
+
+	if previous, ok := b.previousIDs[key]; ok && previous != blockID {
+		log.Printf(
+			"element %q has block ID %q, but was rendered with block ID %q previously, use spanner.WithID to set a stable ID",
+			label,
+			blockID,
+			previous,
+		)
+	}
+}
+
+// fieldBlockID returns the block ID for an element identified by its element ID or label.
+// Element IDs take precedence over labels.
+func (b *Blocks) fieldBlockID(field string) (string, bool) {
+	if blockID, ok := b.elementIDs[field]; ok {
+		return blockID, true
+	}
+	blockID, ok := b.labels[field]
+	return blockID, ok
+}
+
+func optionBlockObjects(options []spanner.Option) []*slack.OptionBlockObject {
+	var optionObjects []*slack.OptionBlockObject
+	for _, option := range options {
+		var description *slack.TextBlockObject
+		if option.Description != "" {
+			description = slack.NewTextBlockObject(slack.PlainTextType, option.Description, false, false)
+		}
+		optionObjects = append(
+			optionObjects,
+			slack.NewOptionBlockObject(
+				option.Value,
+				slack.NewTextBlockObject(slack.PlainTextType, option.Label, false, false),
+				description,
+			),
+		)
+	}
+	return optionObjects
+}
+
 func (m *Blocks) state() map[string]BlockState {
 	if m.BlockStates != nil {
 		return m.BlockStates
@@ -0,0 +1,233 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestStableElementIDs(t *testing.T) {
+	b := &Blocks{
+		BlockStates: map[string]BlockState{
+			"email": {String: "test@example.com"},
+		},
+	}
+
+	// Adding an element before the input should not affect its state
+	b.Button("Conditional")
+	email := b.TextInput("Email", "", "", spanner.WithID("email"))
+	if email != "test@example.com" {
+		t.Errorf("expected email to be read from state, got %q", email)
+	}
+
+	// Elements without an ID should not be affected by elements with an ID
+	b.Button("Submit")
+	if got := b.BlockIDs; len(got) != 2 || got["Conditional"] != "input-0" || got["Submit"] != "input-1" {
+		t.Errorf("unexpected block IDs: %v", got)
+	}
+	if blockID, _ := b.fieldBlockID("email"); blockID != "email" {
+		t.Errorf("expected email to keep its ID, got %q", blockID)
+	}
+}
+
+func TestChangedElementIDWarning(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	b := &Blocks{
+		BlockIDs: map[string]string{"Email": "input-0"},
+	}
+	b.Button("Conditional")
+	b.TextInput("Email", "", "")
+
+	if !strings.Contains(buf.String(), `element "Email" has block ID "input-1"`) {
+		t.Errorf("expected a warning for the changed ID, got: %q", buf.String())
+	}
+}
+
+func TestStableElementIDsDoNotWarn(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	first := &Blocks{}
+	first.TextInput("Email", "", "", spanner.WithID("email"))
+	first.Button("Submit", spanner.WithID("submit"))
+
+	// Adding a conditional element before elements with stable IDs should not warn
+	b := &Blocks{
+		BlockIDs: first.BlockIDs,
+	}
+	b.Button("Conditional", spanner.WithID("conditional"))
+	b.TextInput("Email", "", "", spanner.WithID("email"))
+	b.Button("Submit", spanner.WithID("submit"))
+
+	// Elements sharing a label are told apart by the order they were rendered
+	b.Button("Delete")
+	b.Button("Delete")
+	again := &Blocks{
+		BlockIDs: b.BlockIDs,
+	}
+	again.Button("Conditional", spanner.WithID("conditional"))
+	again.Button("Delete")
+	again.Button("Delete")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no warnings, got: %q", buf.String())
+	}
+
+	// IDs that look like generated IDs are still stable
+	custom := &Blocks{}
+	custom.Button("First")
+	custom.Button("Custom", spanner.WithID("input-0"))
+	if _, tracked := custom.BlockIDs["Custom"]; tracked {
+		t.Errorf("expected element with an ID to not be tracked by position, got %v", custom.BlockIDs)
+	}
+}
+
+func TestDuplicateElementIDsWarn(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	b := &Blocks{}
+	b.Button("Approve", spanner.WithID("decision"))
+	b.Button("Reject", spanner.WithID("decision"))
+
+	if !strings.Contains(buf.String(), `"decision", which is already used by another element`) {
+		t.Errorf("expected a warning about the duplicate ID, got: %q", buf.String())
+	}
+}
+
+func TestDateAndTimePickers(t *testing.T) {
+	states := blockActionToState(eventPopulation{
+		interactionCallbackEvent: slack.InteractionCallback{
+			BlockActionState: &slack.BlockActionStates{
+				Values: map[string]map[string]slack.BlockAction{
+					"input-0": {"input0action": {SelectedDate: "2023-11-05"}},
+					"input-1": {"input1action": {SelectedTime: "13:45"}},
+					"input-2": {"input2action": {SelectedDateTime: 1699191900}},
+				},
+			},
+		},
+	})
+
+	b := &Blocks{BlockStates: states}
+
+	date := b.DatePicker("Date", "")
+	if expected := time.Date(2023, 11, 5, 0, 0, 0, 0, time.UTC); !date.Equal(expected) {
+		t.Errorf("expected date %v, got %v", expected, date)
+	}
+
+	tm := b.TimePicker("Time", "")
+	if tm.Hour() != 13 || tm.Minute() != 45 {
+		t.Errorf("expected time 13:45, got %v", tm.Format("15:04"))
+	}
+
+	dateTime := b.DateTimePicker("Date and time", "")
+	if expected := time.Unix(1699191900, 0); !dateTime.Equal(expected) {
+		t.Errorf("expected date and time %v, got %v", expected, dateTime)
+	}
+
+	if unset := b.DatePicker("Unset", ""); !unset.IsZero() {
+		t.Errorf("expected zero time for unset picker, got %v", unset)
+	}
+}
+
+func TestCheckboxesAndRadioButtonDefaults(t *testing.T) {
+	options := spanner.Options("a", "b", "c")
+
+	b := &Blocks{}
+	checked := b.Checkboxes("Letters", options, spanner.WithDefault("a", "c"))
+	if strings.Join(checked, ",") != "a,c" {
+		t.Errorf("expected default checkboxes to be selected, got %v", checked)
+	}
+	radio := b.RadioButtons("Letter", options, spanner.WithDefault("b"))
+	if radio != "b" {
+		t.Errorf("expected default radio button to be selected, got %q", radio)
+	}
+
+	// Clear all checkboxes and select a different radio button
+	states := blockActionToState(eventPopulation{
+		interactionCallbackEvent: slack.InteractionCallback{
+			BlockActionState: &slack.BlockActionStates{
+				Values: map[string]map[string]slack.BlockAction{
+					b.BlockIDs["Letters"]: {"input0action": {}},
+					b.BlockIDs["Letter"]:  {"input1action": {SelectedOption: slack.OptionBlockObject{Value: "c"}}},
+				},
+			},
+		},
+	})
+
+	// Round trip the state to ensure cleared values are not replaced by defaults
+	stateJSON, err := json.Marshal(states)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = &Blocks{}
+	if err := json.Unmarshal(stateJSON, &b.BlockStates); err != nil {
+		t.Fatal(err)
+	}
+
+	checked = b.Checkboxes("Letters", options, spanner.WithDefault("a", "c"))
+	if len(checked) != 0 {
+		t.Errorf("expected no checkboxes to be selected, got %v", checked)
+	}
+	radio = b.RadioButtons("Letter", options, spanner.WithDefault("b"))
+	if radio != "c" {
+		t.Errorf("expected radio button %q to be selected, got %q", "c", radio)
+	}
+}
+
+func TestUserAndConversationSelects(t *testing.T) {
+	states := blockActionToState(eventPopulation{
+		interactionCallbackEvent: slack.InteractionCallback{
+			BlockActionState: &slack.BlockActionStates{
+				Values: map[string]map[string]slack.BlockAction{
+					"input-0": {"input0action": {SelectedUser: "U123"}},
+					"input-1": {"input1action": {SelectedChannels: []string{"C123", "C456"}}},
+					"input-2": {"input2action": {SelectedConversations: []string{"D123"}}},
+				},
+			},
+		},
+	})
+
+	b := &Blocks{BlockStates: states}
+
+	if u := b.UserSelect("User"); u == nil || u.ID() != "U123" {
+		t.Errorf("expected user U123, got %v", u)
+	}
+
+	channels := b.MultipleChannelSelect("Channels")
+	if len(channels) != 2 || channels[0].ID() != "C123" || channels[1].ID() != "C456" {
+		t.Errorf("unexpected channels: %v", channels)
+	}
+
+	conversations := b.MultipleConversationSelect("Conversations", spanner.ConversationFilter{
+		Include:         []string{"im"},
+		ExcludeBotUsers: true,
+	})
+	if len(conversations) != 1 || conversations[0].ID() != "D123" {
+		t.Errorf("unexpected conversations: %v", conversations)
+	}
+
+	if c := b.ChannelSelect("Unset"); c != nil {
+		t.Errorf("expected nil channel for unset select, got %v", c)
+	}
+
+	rendered, _ := json.Marshal(b.blocks[2])
+	if !strings.Contains(string(rendered), `"filter":{"include":["im"],"exclude_bot_users":true}`) {
+		t.Errorf("expected filter to be rendered, got: %v", string(rendered))
+	}
+	if !strings.Contains(string(rendered), `"initial_conversations":["D123"]`) {
+		t.Errorf("expected initial conversations to be rendered, got: %v", string(rendered))
+	}
+}
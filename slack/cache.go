@@ -0,0 +1,262 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// DefaultMetadataCacheTTL is the TTL of the cache created for an app when no MetadataCache is configured.
+const DefaultMetadataCacheTTL = 5 * time.Minute
+
+// MetadataCache caches details of users and channels across events, to avoid requesting
+// them from Slack for every interaction.
+//
+// Cached users are updated when a user_change event is received and cached channels are
+// removed when a channel_rename event is received. Your app must be subscribed to these
+// events for the cache to see them.
+//
+// A MetadataCache is safe for concurrent use, and may be seeded with PutUser and PutChannel.
+type MetadataCache struct {
+	ttl time.Duration
+
+	mtx      sync.Mutex
+	users    map[string]cachedUser
+	channels map[string]cachedChannel
+	stats    CacheStats
+}
+
+type cachedUser struct {
+	user    slack.User
+	expires time.Time
+}
+
+type cachedChannel struct {
+	channel slack.Channel
+	expires time.Time
+}
+
+// CacheStats reports how often a MetadataCache was able to answer a request.
+type CacheStats struct {
+	UserHits      int64
+	UserMisses    int64
+	ChannelHits   int64
+	ChannelMisses int64
+}
+
+// HitRate returns the fraction of requests for users and channels that were answered from the cache.
+// If there have been no requests, HitRate returns 0.
+func (s CacheStats) HitRate() float64 {
+	hits := s.UserHits + s.ChannelHits
+	total := hits + s.UserMisses + s.ChannelMisses
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total)
+}
+
+// NewMetadataCache creates a cache for user and channel details.
+// Entries expire after ttl, if ttl is zero, entries never expire.
+func NewMetadataCache(ttl time.Duration) *MetadataCache {
+	return &MetadataCache{
+		ttl:      ttl,
+		users:    make(map[string]cachedUser),
+		channels: make(map[string]cachedChannel),
+	}
+}
+
+// PutUser adds or replaces the details of a user.
+func (c *MetadataCache) PutUser(user slack.User) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.users[user.ID] = cachedUser{
+		user:    user,
+		expires: c.expiry(),
+	}
+}
+
+// PutChannel adds or replaces the details of a channel.
+func (c *MetadataCache) PutChannel(channel slack.Channel) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.channels[channel.ID] = cachedChannel{
+		channel: channel,
+		expires: c.expiry(),
+	}
+}
+
+// InvalidateUser removes a user from the cache, so their details will be requested from Slack when next needed.
+func (c *MetadataCache) InvalidateUser(userID string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.users, userID)
+}
+
+// InvalidateChannel removes a channel from the cache, so its details will be requested from Slack when next needed.
+func (c *MetadataCache) InvalidateChannel(channelID string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.channels, channelID)
+}
+
+// Stats returns the number of cache hits and misses so far.
+func (c *MetadataCache) Stats() CacheStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.stats
+}
+
+func (c *MetadataCache) user(userID string) (*slack.User, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entry, ok := c.users[userID]
+	if ok && c.expired(entry.expires) {
+		delete(c.users, userID)
+		ok = false
+	}
+	if !ok {
+		c.stats.UserMisses++
+		return nil, false
+	}
+	c.stats.UserHits++
+	user := entry.user
+	return &user, true
+}
+
+func (c *MetadataCache) channel(channelID string) (*slack.Channel, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entry, ok := c.channels[channelID]
+	if ok && c.expired(entry.expires) {
+		delete(c.channels, channelID)
+		ok = false
+	}
+	if !ok {
+		c.stats.ChannelMisses++
+		return nil, false
+	}
+	c.stats.ChannelHits++
+	channel := entry.channel
+	return &channel, true
+}
+
+func (c *MetadataCache) expiry() time.Time {
+	if c.ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(c.ttl)
+}
+
+func (c *MetadataCache) expired(expires time.Time) bool {
+	return !expires.IsZero() && time.Now().After(expires)
+}
+
+// update applies changes to users and channels reported by an event.
+func (c *MetadataCache) update(ev *socketmode.Event) {
+	if ev == nil {
+		return
+	}
+	eventsAPIEvent, ok := ev.Data.(slackevents.EventsAPIEvent)
+	if !ok {
+		return
+	}
+
+	switch inner := eventsAPIEvent.InnerEvent.Data.(type) {
+	case *slack.UserChangeEvent:
+		c.PutUser(inner.User)
+	case *slackevents.ChannelRenameEvent:
+		c.InvalidateChannel(inner.Channel.ID)
+	}
+}
+
+// prefetch loads all users and channels in the workspace into the cache.
+func (c *MetadataCache) prefetch(ctx context.Context, client socketClient) error {
+	users, err := client.GetUsersContext(ctx)
+	if err != nil {
+		return fmt.Errorf("listing users: %w", renderSlackError(err))
+	}
+	for _, user := range users {
+		c.PutUser(user)
+	}
+
+	params := &slack.GetConversationsParameters{
+		ExcludeArchived: true,
+		Limit:           1000,
+		Types:           []string{"public_channel", "private_channel"},
+	}
+	for {
+		channels, cursor, err := client.GetConversationsContext(ctx, params)
+		if err != nil {
+			return fmt.Errorf("listing channels: %w", renderSlackError(err))
+		}
+		for _, channel := range channels {
+			c.PutChannel(channel)
+		}
+		if cursor == "" {
+			return nil
+		}
+		params.Cursor = cursor
+	}
+}
+
+// cachingClient is a socketClient that answers requests for user and channel details
+// from a MetadataCache when possible.
+// Channels are removed from the cache when they are changed through the client.
+type cachingClient struct {
+	socketClient
+
+	cache *MetadataCache
+}
+
+func (c *cachingClient) GetUserInfoContext(ctx context.Context, userID string) (*slack.User, error) {
+	if user, ok := c.cache.user(userID); ok {
+		return user, nil
+	}
+
+	user, err := c.socketClient.GetUserInfoContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.PutUser(*user)
+	return user, nil
+}
+
+func (c *cachingClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	if channel, ok := c.cache.channel(input.ChannelID); ok {
+		return channel, nil
+	}
+
+	channel, err := c.socketClient.GetConversationInfoContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.PutChannel(*channel)
+	return channel, nil
+}
+
+func (c *cachingClient) SetTopicOfConversationContext(ctx context.Context, channelID string, topic string) (*slack.Channel, error) {
+	defer c.cache.InvalidateChannel(channelID)
+	return c.socketClient.SetTopicOfConversationContext(ctx, channelID, topic)
+}
+
+func (c *cachingClient) SetPurposeOfConversationContext(ctx context.Context, channelID string, purpose string) (*slack.Channel, error) {
+	defer c.cache.InvalidateChannel(channelID)
+	return c.socketClient.SetPurposeOfConversationContext(ctx, channelID, purpose)
+}
+
+func (c *cachingClient) ArchiveConversationContext(ctx context.Context, channelID string) error {
+	defer c.cache.InvalidateChannel(channelID)
+	return c.socketClient.ArchiveConversationContext(ctx, channelID)
+}
@@ -0,0 +1,150 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/theothertomelliott/spanner"
+)
+
+func realNameHandler(ctx context.Context, ev spanner.Event) {
+	if msg := ev.ReceiveMessage(); msg != nil {
+		ev.SendMessage(msg.Channel().ID()).PlainText(
+			"user: " + msg.User().RealName(ctx) + ", channel: " + msg.Channel().Name(ctx),
+		)
+	}
+}
+
+func TestMetadataCache(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	client.users = map[string]*slack.User{
+		"DEF456": {ID: "DEF456", RealName: "Jane Doe"},
+	}
+	cache := NewMetadataCache(time.Minute)
+	testApp := newAppWithClient(
+		client,
+		AppConfig{
+			EventInterceptor: client.EventInterceptor,
+			MetadataCache:    cache,
+		},
+		client.Events,
+	)
+
+	go func() {
+		err := testApp.Run(realNameHandler)
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	hello := messageEvent(slackevents.MessageEvent{
+		Text:    "hello",
+		Channel: "ABC123",
+		User:    "DEF456",
+	})
+	client.SendEventToApp(hello)
+	client.SendEventToApp(hello)
+
+	if client.userInfoRequests != 1 || client.channelInfoRequests != 1 {
+		t.Errorf("expected one request each for user and channel, got %d and %d", client.userInfoRequests, client.channelInfoRequests)
+	}
+	stats := cache.Stats()
+	if stats.UserHits != 1 || stats.UserMisses != 1 || stats.ChannelHits != 1 || stats.ChannelMisses != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if rate := stats.HitRate(); rate != 0.5 {
+		t.Errorf("expected hit rate of 0.5, got %v", rate)
+	}
+
+	// A user change should update the cached user without a request
+	client.SendEventToApp(userChangeEvent(slack.User{ID: "DEF456", RealName: "Jane Smith"}))
+	// A channel rename should cause the channel to be requested again
+	client.SendEventToApp(channelRenameEvent("ABC123", "renamed"))
+	client.SendEventToApp(hello)
+
+	if client.userInfoRequests != 1 || client.channelInfoRequests != 2 {
+		t.Errorf("expected user to be updated and channel requested again, got %d and %d requests", client.userInfoRequests, client.channelInfoRequests)
+	}
+	if len(client.messagesSent) != 3 {
+		t.Fatalf("expected three messages, got %d", len(client.messagesSent))
+	}
+	blocks, _ := json.Marshal(client.messagesSent[2].blocks)
+	if !strings.Contains(string(blocks), "user: Jane Smith") {
+		t.Errorf("expected updated user name, got: %v", string(blocks))
+	}
+}
+
+func TestMetadataCacheSeeded(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	cache := NewMetadataCache(0)
+	cache.PutUser(slack.User{ID: "DEF456", RealName: "Seeded User"})
+	seededChannel := slack.Channel{}
+	seededChannel.ID = "ABC123"
+	seededChannel.Name = "seeded"
+	cache.PutChannel(seededChannel)
+
+	testApp := newAppWithClient(
+		client,
+		AppConfig{
+			EventInterceptor: client.EventInterceptor,
+			MetadataCache:    cache,
+		},
+		client.Events,
+	)
+
+	go func() {
+		err := testApp.Run(realNameHandler)
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:    "hello",
+		Channel: "ABC123",
+		User:    "DEF456",
+	}))
+
+	if client.userInfoRequests != 0 || client.channelInfoRequests != 0 {
+		t.Errorf("expected no requests, got %d and %d", client.userInfoRequests, client.channelInfoRequests)
+	}
+	blocks, _ := json.Marshal(client.messagesSent[0].blocks)
+	if !strings.Contains(string(blocks), "user: Seeded User, channel: seeded") {
+		t.Errorf("expected seeded details, got: %v", string(blocks))
+	}
+}
+
+func TestMetadataCacheExpiry(t *testing.T) {
+	cache := NewMetadataCache(time.Millisecond)
+	cache.PutUser(slack.User{ID: "DEF456"})
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.user("DEF456"); ok {
+		t.Errorf("expected user to have expired")
+	}
+}
+
+func TestMetadataCachePrefetch(t *testing.T) {
+	client := newTestClient([]string{"ABC123", "GHI789"})
+	client.users = map[string]*slack.User{
+		"DEF456": {ID: "DEF456"},
+	}
+	cache := NewMetadataCache(time.Minute)
+
+	if err := cache.prefetch(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"ABC123", "GHI789"} {
+		if _, ok := cache.channel(id); !ok {
+			t.Errorf("expected channel %v to be cached", id)
+		}
+	}
+	if _, ok := cache.user("DEF456"); !ok {
+		t.Errorf("expected user to be cached")
+	}
+}
@@ -2,6 +2,9 @@ package slack
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"strings"
 
 	"github.com/slack-go/slack"
 	"github.com/theothertomelliott/spanner"
@@ -15,7 +18,20 @@ type channel struct {
 	IDInternal   string `json:"id"`
 	NameInternal string `json:"name"`
 
+	IsPrivateInternal bool   `json:"is_private,omitempty"`
+	IsIMInternal      bool   `json:"is_im,omitempty"`
+	IsSharedInternal  bool   `json:"is_shared,omitempty"`
+	TopicInternal     string `json:"topic,omitempty"`
+	PurposeInternal   string `json:"purpose,omitempty"`
+
 	Loaded bool `json:"loaded"`
+
+	// Members are loaded separately, and only when needed
+	members       []string
+	membersLoaded bool
+	membersErr    error
+
+	loadErr error
 }
 
 func (c *channel) ID() string {
@@ -23,23 +39,98 @@ func (c *channel) ID() string {
 }
 
 func (c *channel) Name(ctx context.Context) string {
-	c.load(ctx)
+	// The name may be known without loading the channel, such as for slash commands
+	if c.NameInternal == "" {
+		c.load(ctx)
+	}
 	return c.NameInternal
 }
 
-func (c *channel) load(ctx context.Context) {
+func (c *channel) IsPrivate(ctx context.Context) bool {
+	c.load(ctx)
+	return c.IsPrivateInternal
+}
+
+func (c *channel) IsIM(ctx context.Context) bool {
+	c.load(ctx)
+	return c.IsIMInternal
+}
+
+func (c *channel) IsShared(ctx context.Context) bool {
+	c.load(ctx)
+	return c.IsSharedInternal
+}
+
+func (c *channel) Topic(ctx context.Context) string {
+	c.load(ctx)
+	return c.TopicInternal
+}
+
+func (c *channel) Purpose(ctx context.Context) string {
+	c.load(ctx)
+	return c.PurposeInternal
+}
+
+func (c *channel) Members(ctx context.Context) []string {
+	if c.membersLoaded || c.membersErr != nil {
+		return c.members
+	}
+
+	var members []string
+	params := &slack.GetUsersInConversationParameters{
+		ChannelID: c.IDInternal,
+		Limit:     1000,
+	}
+	for {
+		page, cursor, err := c.client.GetUsersInConversationContext(ctx, params)
+		if err != nil {
+			c.membersErr = fmt.Errorf("loading members of channel %v: %w", c.IDInternal, renderSlackError(err))
+			log.Print(c.membersErr)
+			return nil
+		}
+		members = append(members, page...)
+		if cursor == "" {
+			break
+		}
+		params.Cursor = cursor
+	}
+
+	c.members = members
+	c.membersLoaded = true
+	return c.members
+}
+
+func (c *channel) Load(ctx context.Context) error {
 	if c.Loaded {
-		return
+		return nil
+	}
+	// Don't repeat failed requests for the same event
+	if c.loadErr != nil {
+		return c.loadErr
 	}
 
 	ch, err := c.client.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
 		ChannelID: c.IDInternal,
 	})
 	if err != nil {
-		panic(err)
+		c.loadErr = fmt.Errorf("loading channel %v: %w", c.IDInternal, renderSlackError(err))
+		return c.loadErr
 	}
 	c.Loaded = true
 	c.NameInternal = ch.Name
+	c.IsPrivateInternal = ch.IsPrivate
+	c.IsIMInternal = ch.IsIM
+	c.IsSharedInternal = ch.IsShared || ch.IsExtShared
+	c.TopicInternal = ch.Topic.Value
+	c.PurposeInternal = ch.Purpose.Value
+	return nil
+}
+
+// load loads the channel, logging any error.
+func (c *channel) load(ctx context.Context) {
+	if err := c.Load(ctx); err != nil {
+		log.Print(err)
+	}
 }
 
 var _ action = &joinChannelAction{}
@@ -72,9 +163,154 @@ func (*joinChannelAction) Type() string {
 
 // exec implements action.
 func (a *joinChannelAction) exec(ctx context.Context, req request) (interface{}, error) {
-	_, _, _, err := req.client.JoinConversationContext(ctx, a.channelID)
+	_, _, _, err := req.client.JoinConversationContext(ctx, req.channelID(a.channelID))
 	if err != nil {
 		return nil, err
 	}
 	return nil, nil
 }
+
+var _ action = &channelAction{}
+
+// channelAction manages an existing channel.
+// The operation is identified by its type, with the topic or purpose in value, and any users to
+// invite or kick in userIDs.
+type channelAction struct {
+	actionType string
+	channelID  string
+	value      string
+	userIDs    []string
+	errFunc    spanner.ErrorFunc
+}
+
+const (
+	setChannelTopicAction   = "set_channel_topic"
+	setChannelPurposeAction = "set_channel_purpose"
+	inviteToChannelAction   = "invite_to_channel"
+	kickFromChannelAction   = "kick_from_channel"
+	archiveChannelAction    = "archive_channel"
+	leaveChannelAction      = "leave_channel"
+)
+
+func (c *channelAction) ErrorFunc(ef spanner.ErrorFunc) {
+	c.errFunc = ef
+}
+
+func (c *channelAction) getErrorFunc() spanner.ErrorFunc {
+	return c.errFunc
+}
+
+// Data implements action.
+func (c *channelAction) Data() interface{} {
+	// TODO: This should be more well-defined
+	data := map[string]interface{}{
+		"channel_id": c.channelID,
+	}
+	if c.value != "" {
+		data["value"] = c.value
+	}
+	if len(c.userIDs) > 0 {
+		data["user_ids"] = c.userIDs
+	}
+	return data
+}
+
+// Type implements action.
+func (c *channelAction) Type() string {
+	return c.actionType
+}
+
+// exec implements action.
+func (c *channelAction) exec(ctx context.Context, req request) (interface{}, error) {
+	c.channelID = req.channelID(c.channelID)
+
+	var err error
+	switch c.actionType {
+	case setChannelTopicAction:
+		_, err = req.client.SetTopicOfConversationContext(ctx, c.channelID, c.value)
+	case setChannelPurposeAction:
+		_, err = req.client.SetPurposeOfConversationContext(ctx, c.channelID, c.value)
+	case inviteToChannelAction:
+		_, err = req.client.InviteUsersToConversationContext(ctx, c.channelID, c.userIDs...)
+	case kickFromChannelAction:
+		for _, userID := range c.userIDs {
+			if err = req.client.KickUserFromConversationContext(ctx, c.channelID, userID); err != nil {
+				break
+			}
+		}
+	case archiveChannelAction:
+		err = req.client.ArchiveConversationContext(ctx, c.channelID)
+	case leaveChannelAction:
+		_, err = req.client.LeaveConversationContext(ctx, c.channelID)
+	default:
+		err = fmt.Errorf("unknown action")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", strings.ReplaceAll(c.actionType, "_", " "), renderSlackError(err))
+	}
+	return nil, nil
+}
+
+var _ action = &createChannelAction{}
+var _ spanner.ChannelHandle = &createChannelAction{}
+
+// createChannelAction creates a new channel.
+// Until the channel is created, it is referred to by a placeholder ID, which is replaced
+// in the actions that follow.
+type createChannelAction struct {
+	placeholder string
+	name        string
+	private     bool
+	errFunc     spanner.ErrorFunc
+
+	// channelID is set once the channel has been created
+	channelID string
+}
+
+func (c *createChannelAction) ID() string {
+	if c.channelID != "" {
+		return c.channelID
+	}
+	return c.placeholder
+}
+
+func (c *createChannelAction) ErrorFunc(ef spanner.ErrorFunc) {
+	c.errFunc = ef
+}
+
+func (c *createChannelAction) getErrorFunc() spanner.ErrorFunc {
+	return c.errFunc
+}
+
+// Data implements action.
+func (c *createChannelAction) Data() interface{} {
+	// TODO: This should be more well-defined
+	return map[string]interface{}{
+		"name":    c.name,
+		"private": c.private,
+	}
+}
+
+// Type implements action.
+func (*createChannelAction) Type() string {
+	return "create_channel"
+}
+
+// exec implements action.
+func (c *createChannelAction) exec(ctx context.Context, req request) (interface{}, error) {
+	ch, err := req.client.CreateConversationContext(ctx, slack.CreateConversationParams{
+		ChannelName: c.name,
+		IsPrivate:   c.private,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating channel: %w", renderSlackError(err))
+	}
+	c.channelID = ch.ID
+
+	// Save the new channel's ID in the state, so it isn't created again by later events
+	if req.es.state.CreatedChannels == nil {
+		req.es.state.CreatedChannels = make(map[string]string)
+	}
+	req.es.state.CreatedChannels[c.placeholder] = ch.ID
+	return nil, nil
+}
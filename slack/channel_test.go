@@ -0,0 +1,253 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestChannelDetails(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	c := &channel{client: client, IDInternal: "ABC123"}
+	ctx := context.Background()
+
+	if !c.IsPrivate(ctx) || c.IsIM(ctx) || c.IsShared(ctx) {
+		t.Errorf("expected a private, unshared channel")
+	}
+	if topic := c.Topic(ctx); topic != "topic-ABC123" {
+		t.Errorf("unexpected topic: %q", topic)
+	}
+	if members := c.Members(ctx); strings.Join(members, ",") != "DEF456,GHI789" {
+		t.Errorf("expected members from all pages, got %v", members)
+	}
+
+	missing := &channel{client: client, IDInternal: "missing"}
+	if err := missing.Load(ctx); err == nil {
+		t.Errorf("expected an error loading a missing channel")
+	}
+	if members := missing.Members(ctx); members != nil {
+		t.Errorf("expected no members for a missing channel, got %v", members)
+	}
+}
+
+func TestSlashCommandChannelDetails(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var (
+		name      string
+		isPrivate bool
+		topic     string
+	)
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if cmd := ev.ReceiveSlashCommand("/status"); cmd != nil {
+				name = cmd.Channel().Name(ctx)
+				isPrivate = cmd.Channel().IsPrivate(ctx)
+				topic = cmd.Channel().Topic(ctx)
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(slashCommandEvent(slack.SlashCommand{
+		Command:     "/status",
+		ChannelID:   "ABC123",
+		ChannelName: "incidents",
+		UserID:      "DEF456",
+	}))
+
+	if name != "incidents" {
+		t.Errorf("expected the channel name from the command, got %q", name)
+	}
+	if !isPrivate || topic != "topic-ABC123" {
+		t.Errorf("expected channel details to be loaded, got private=%v, topic=%q", isPrivate, topic)
+	}
+	if client.channelInfoRequests != 1 {
+		t.Errorf("expected the channel to be loaded once, got %d requests", client.channelInfoRequests)
+	}
+}
+
+func TestChannelActions(t *testing.T) {
+	client := newTestClient([]string{"ABC123", "WAR123"})
+	cache := NewMetadataCache(time.Minute)
+	testApp := newAppWithClient(
+		client,
+		AppConfig{
+			EventInterceptor: client.EventInterceptor,
+			MetadataCache:    cache,
+		},
+		client.Events,
+	)
+
+	var archiveErr error
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if msg := ev.ReceiveMessage(); msg != nil && msg.Text() == "incident" {
+				ev.SetChannelTopic("WAR123", "Investigating")
+				ev.SetChannelPurpose("WAR123", "Incident response")
+				ev.InviteToChannel("WAR123", "DEF456", "GHI789")
+				ev.KickFromChannel("WAR123", "GHI789")
+				ev.LeaveChannel("WAR123")
+				ev.ArchiveChannel("invalid_channel").ErrorFunc(func(ctx context.Context, ev spanner.ErrorEvent) {
+					archiveErr = ev.ReceiveError()
+				})
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	// Cache the channel so we can verify changes invalidate it
+	c := &channel{client: &cachingClient{socketClient: client, cache: cache}, IDInternal: "WAR123"}
+	c.Load(context.Background())
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:    "incident",
+		Channel: "ABC123",
+		User:    "DEF456",
+	}))
+
+	var actions []string
+	for _, change := range client.channelChanges {
+		if change.channelID != "WAR123" {
+			t.Errorf("unexpected channel: %v", change.channelID)
+		}
+		actions = append(actions, change.action+":"+change.value+change.userID)
+	}
+	if got := strings.Join(actions, " "); got != "topic:Investigating purpose:Incident response invite:DEF456,GHI789 kick:GHI789 leave:" {
+		t.Errorf("unexpected channel changes: %v", got)
+	}
+	if archiveErr == nil || !strings.Contains(archiveErr.Error(), "archive channel") {
+		t.Errorf("expected an error archiving an invalid channel, got %v", archiveErr)
+	}
+	if _, ok := cache.channel("WAR123"); ok {
+		t.Errorf("expected changed channel to be removed from the cache")
+	}
+}
+
+func TestCreateChannel(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var createErr error
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if msg := ev.ReceiveMessage(); msg != nil && msg.Text() == "incident" {
+				warRoom := ev.CreateChannel("incident-123", true)
+				ev.InviteToChannel(warRoom.ID(), "DEF456")
+				ev.SetChannelTopic(warRoom.ID(), "Investigating")
+				summary := ev.SendMessage(warRoom.ID())
+				summary.PlainText("Incident summary")
+				summary.Button("Resolve")
+
+				ev.CreateChannel("", false).ErrorFunc(func(ctx context.Context, ev spanner.ErrorEvent) {
+					createErr = ev.ReceiveError()
+				})
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:    "incident",
+		Channel: "ABC123",
+		User:    "DEF456",
+	}))
+
+	if len(client.channelChanges) != 2 {
+		t.Fatalf("expected two channel changes, got %d", len(client.channelChanges))
+	}
+	for _, change := range client.channelChanges {
+		if change.channelID != "NEW1" {
+			t.Errorf("expected change to the new channel, got %+v", change)
+		}
+	}
+	if len(client.messagesSent) != 1 || client.messagesSent[0].channelID != "NEW1" {
+		t.Fatalf("expected message to be sent to the new channel, got %+v", client.messagesSent)
+	}
+	metadata, _ := client.messagesSent[0].metadata.EventPayload["metadata"].(string)
+	if strings.Contains(metadata, `"channel_id":"new-channel-`) {
+		t.Errorf("expected placeholder to be replaced in state, got: %v", metadata)
+	}
+	if createErr == nil {
+		t.Errorf("expected an error creating a channel without a name")
+	}
+
+	// Interacting with the summary should not create the channel again
+	channels := len(client.validChannels)
+	client.messagesUpdated = nil
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		"1700000000.000001",
+		client.messagesSent[0].metadata,
+		buttonClick("input-0", "Resolve"),
+		nil,
+	))
+	if len(client.validChannels) != channels {
+		t.Errorf("expected no new channels, got %d", len(client.validChannels)-channels)
+	}
+	if len(client.messagesUpdated) != 1 || client.messagesUpdated[0].channelID != "NEW1" {
+		t.Errorf("expected the summary in the new channel to be updated, got %+v", client.messagesUpdated)
+	}
+}
+
+func TestCreateChannelOnlyForSomeInteractions(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if msg := ev.ReceiveMessage(); msg != nil && msg.Text() == "incident" {
+				controls := ev.SendMessage(msg.Channel().ID())
+				if controls.Button("Escalate") {
+					ev.CreateChannel("incident-123-escalation", true)
+				}
+
+				warRoom := ev.CreateChannel("incident-123", true)
+				ev.SendMessage(warRoom.ID()).PlainText("Incident summary")
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:    "incident",
+		Channel: "ABC123",
+		User:    "DEF456",
+	}))
+	if len(client.messagesSent) != 2 || client.messagesSent[1].channelID != "NEW1" {
+		t.Fatalf("expected summary to be sent to the new channel, got %+v", client.messagesSent)
+	}
+	if len(client.messagesUpdated) != 1 {
+		t.Fatalf("expected controls to be refreshed, got %+v", client.messagesUpdated)
+	}
+
+	// Escalating creates one more channel, without creating the war room again
+	channels := len(client.validChannels)
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		"1700000000.000001",
+		client.messagesUpdated[0].metadata,
+		buttonClick("input-0", "Escalate"),
+		nil,
+	))
+	if created := len(client.validChannels) - channels; created != 1 {
+		t.Errorf("expected one new channel, got %d", created)
+	}
+	if len(client.messagesSent) != 2 {
+		t.Errorf("expected no new messages, got %+v", client.messagesSent[2:])
+	}
+}
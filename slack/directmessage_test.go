@@ -0,0 +1,74 @@
+package slack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestDirectMessages(t *testing.T) {
+	client := newTestClient([]string{"ABC123", "DDEF456", "DGHI789"})
+	testApp := client.CreateApp()
+
+	var acknowledged bool
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if slash := ev.ReceiveSlashCommand("/remind"); slash != nil {
+				reminder := slash.User().SendMessage()
+				reminder.PlainText("Don't forget!")
+				if reminder.Button("Got it") {
+					acknowledged = true
+				}
+
+				ev.SendDirectMessage("GHI789").PlainText("A reminder was sent")
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(slashCommandEvent(slack.SlashCommand{
+		Command:   "/remind",
+		ChannelID: "ABC123",
+		UserID:    "DEF456",
+	}))
+
+	if len(client.messagesSent) != 2 {
+		t.Fatalf("expected two messages to be sent, got %d", len(client.messagesSent))
+	}
+	if got := client.messagesSent[0].channelID; got != "DDEF456" {
+		t.Errorf("expected message to be sent to DDEF456, got %q", got)
+	}
+	if got := client.messagesSent[1].channelID; got != "DGHI789" {
+		t.Errorf("expected message to be sent to DGHI789, got %q", got)
+	}
+
+	// The first message is refreshed with the state of the second
+	if len(client.messagesUpdated) != 1 || client.messagesUpdated[0].timestamp != "1700000000.000001" {
+		t.Fatalf("expected first message to be refreshed, got %+v", client.messagesUpdated)
+	}
+	metadata := client.messagesUpdated[0].metadata
+	client.messagesUpdated = nil
+
+	// Interacting with the direct message should update it
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		"1700000000.000001",
+		metadata,
+		buttonClick("input-0", "Got it"),
+		nil,
+	))
+
+	if !acknowledged {
+		t.Errorf("expected button in direct message to be clicked")
+	}
+	if len(client.messagesSent) != 2 {
+		t.Errorf("expected no new messages, got %d", len(client.messagesSent))
+	}
+	if len(client.messagesUpdated) != 1 || client.messagesUpdated[0].channelID != "DDEF456" {
+		t.Errorf("expected direct message to be updated, got %+v", client.messagesUpdated)
+	}
+}
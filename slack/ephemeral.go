@@ -2,7 +2,11 @@ package slack
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"log"
 
+	"github.com/slack-go/slack"
 	"github.com/theothertomelliott/spanner"
 )
 
@@ -62,3 +66,133 @@ func (e *sendEphemeralMessageAction) exec(ctx context.Context, req request) (int
 	}
 	return payload, nil
 }
+
+// maxButtonValueLength is the maximum length of a button value allowed by Slack.
+const maxButtonValueLength = 2000
+
+// ephemeralState is stored in the value of each button in an ephemeral message,
+// since ephemeral messages cannot include metadata.
+type ephemeralState struct {
+	MessageIndex string `json:"message_index"`
+	Metadata     string `json:"metadata"`
+}
+
+// ephemeralActionState returns the message index and state from the button clicked in an ephemeral message.
+// Only buttons carry state, so interactions with other elements are logged and ignored.
+func ephemeralActionState(ic slack.InteractionCallback) (messageIndex string, metadata string, ok bool) {
+	for _, action := range ic.ActionCallback.BlockActions {
+		if action.Type != "button" {
+			continue
+		}
+		var state ephemeralState
+		if err := json.Unmarshal([]byte(action.Value), &state); err != nil || state.Metadata == "" {
+			continue
+		}
+		return state.MessageIndex, state.Metadata, true
+	}
+	log.Print("ignoring interaction with an ephemeral message: only buttons can be used in ephemeral messages")
+	return "", "", false
+}
+
+// hasNonButtonElements returns true if blocks include interactive elements other than buttons,
+// which cannot carry state in an ephemeral message.
+func hasNonButtonElements(blocks []slack.Block) bool {
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *slack.InputBlock:
+			return true
+		case *slack.ActionBlock:
+			if b.Elements == nil {
+				continue
+			}
+			for _, element := range b.Elements.ElementSet {
+				if _, ok := element.(*slack.ButtonBlockElement); !ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// execEphemeral sends an ephemeral message, or updates one in response to a button click.
+// Ephemeral messages cannot be updated by the API, so changes are made through the response URL
+// of the interaction.
+func (m *message) execEphemeral(ctx context.Context, req request) (interface{}, error) {
+	isCurrent := m.MessageIndex == m.currentMessageIndex && m.EventDepth == m.currentEventDepth
+	if !m.unsent && !isCurrent {
+		return nil, nil
+	}
+
+	if m.Deleted {
+		if m.unsent {
+			return nil, nil
+		}
+		_, _, _, err := req.client.SendMessageContext(ctx, m.ChannelID, slack.MsgOptionDeleteOriginal(m.responseURL))
+		if err != nil {
+			return nil, fmt.Errorf("deleting ephemeral message: %w", renderSlackError(err))
+		}
+		return nil, nil
+	}
+
+	metadata, err := req.Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sending ephemeral message: %w", err)
+	}
+	if err := m.setButtonState(string(metadata)); err != nil {
+		return nil, fmt.Errorf("sending ephemeral message: %w", err)
+	}
+	if hasNonButtonElements(m.blocks) {
+		log.Printf("ephemeral message %v has interactive elements other than buttons, interacting with them will not call your handler", m.MessageIndex)
+	}
+
+	if m.unsent {
+		options := []slack.MsgOption{slack.MsgOptionBlocks(m.blocks...)}
+		if m.ThreadTS != "" {
+			options = append(options, slack.MsgOptionTS(m.ThreadTS))
+		}
+		_, err := req.client.PostEphemeralContext(ctx, m.ChannelID, m.UserID, options...)
+		if err != nil {
+			return nil, fmt.Errorf("sending ephemeral message: %w", renderSlackError(err))
+		}
+		return nil, nil
+	}
+
+	_, _, _, err = req.client.SendMessageContext(
+		ctx,
+		m.ChannelID,
+		slack.MsgOptionReplaceOriginal(m.responseURL),
+		slack.MsgOptionBlocks(m.blocks...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("updating ephemeral message: %w", renderSlackError(err))
+	}
+	return nil, nil
+}
+
+// setButtonState stores the event state in the value of every button in the message.
+func (m *message) setButtonState(metadata string) error {
+	value, err := json.Marshal(ephemeralState{
+		MessageIndex: m.MessageIndex,
+		Metadata:     metadata,
+	})
+	if err != nil {
+		return err
+	}
+	if len(value) > maxButtonValueLength {
+		return fmt.Errorf("state is %d bytes, more than the %d allowed for an ephemeral message, a StateStore can be used to reduce this", len(value), maxButtonValueLength)
+	}
+
+	for _, block := range m.blocks {
+		actions, ok := block.(*slack.ActionBlock)
+		if !ok || actions.Elements == nil {
+			continue
+		}
+		for _, element := range actions.Elements.ElementSet {
+			if button, ok := element.(*slack.ButtonBlockElement); ok {
+				button.Value = string(value)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestEphemeralMessageInteraction(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if slash := ev.ReceiveSlashCommand("/secret"); slash != nil {
+				msg := ev.SendEphemeral(slash.User().ID(), slash.Channel().ID())
+				if msg.Button("Reveal") {
+					msg.PlainText("The secret is 42")
+				} else {
+					msg.PlainText("Click to reveal the secret")
+				}
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(slashCommandEvent(slack.SlashCommand{
+		Command:   "/secret",
+		ChannelID: "ABC123",
+		UserID:    "DEF456",
+	}))
+
+	if len(client.ephemeralSent) != 1 {
+		t.Fatalf("expected one ephemeral message, got %d", len(client.ephemeralSent))
+	}
+	sent := client.ephemeralSent[0]
+	if sent.channelID != "ABC123" || sent.userID != "DEF456" {
+		t.Errorf("unexpected recipient: channel %q, user %q", sent.channelID, sent.userID)
+	}
+
+	var blocks slack.Blocks
+	if err := json.Unmarshal([]byte(sent.values.Get("blocks")), &blocks); err != nil {
+		t.Fatal(err)
+	}
+	actions, ok := blocks.BlockSet[0].(*slack.ActionBlock)
+	if !ok {
+		t.Fatalf("expected first block to be an action block, got %T", blocks.BlockSet[0])
+	}
+	button := actions.Elements.ElementSet[0].(*slack.ButtonBlockElement)
+	if !strings.Contains(button.Value, `"metadata"`) {
+		t.Errorf("expected button value to contain state, got %q", button.Value)
+	}
+
+	client.SendEventToApp(interactionEvent(slack.InteractionCallback{
+		Type:        slack.InteractionTypeBlockActions,
+		ResponseURL: "https://hooks.slack.com/actions/response",
+		Container: slack.Container{
+			Type:        "message",
+			ChannelID:   "ABC123",
+			IsEphemeral: true,
+		},
+		ActionCallback: slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{
+				{
+					Type:    "button",
+					BlockID: "input-0",
+					Text:    slack.TextBlockObject{Text: "Reveal"},
+					Value:   button.Value,
+				},
+			},
+		},
+	}))
+
+	if len(client.ephemeralSent) != 1 {
+		t.Errorf("expected no new ephemeral messages, got %d", len(client.ephemeralSent))
+	}
+	if len(client.responses) != 1 {
+		t.Fatalf("expected one response, got %d", len(client.responses))
+	}
+	response := client.responses[0]
+	if response.responseURL != "https://hooks.slack.com/actions/response" {
+		t.Errorf("expected message to be replaced through the response URL, got %q", response.responseURL)
+	}
+	if !strings.Contains(response.values.Get("blocks"), "The secret is 42") {
+		t.Errorf("expected updated content, got %v", response.values.Get("blocks"))
+	}
+}
+
+func TestEphemeralMessageWithoutButtonsWarns(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var calls int
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			calls++
+			if slash := ev.ReceiveSlashCommand("/secret"); slash != nil {
+				msg := ev.SendEphemeral(slash.User().ID(), slash.Channel().ID())
+				msg.UserSelect("Assignee")
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(slashCommandEvent(slack.SlashCommand{
+		Command:   "/secret",
+		ChannelID: "ABC123",
+		UserID:    "DEF456",
+	}))
+	if !strings.Contains(buf.String(), "interactive elements other than buttons") {
+		t.Errorf("expected a warning about elements other than buttons, got: %q", buf.String())
+	}
+
+	// Interacting with the select can't restore the event's state
+	client.SendEventToApp(interactionEvent(slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		Container: slack.Container{
+			Type:        "message",
+			ChannelID:   "ABC123",
+			IsEphemeral: true,
+		},
+		ActionCallback: slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{
+				{Type: "users_select", BlockID: "input-0"},
+			},
+		},
+	}))
+	if !strings.Contains(buf.String(), "ignoring interaction with an ephemeral message") {
+		t.Errorf("expected the interaction to be logged, got: %q", buf.String())
+	}
+	if calls != 2 {
+		t.Errorf("expected the handler to be called without state, got %d calls", calls)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 
 	"github.com/slack-go/slack"
@@ -35,7 +36,15 @@ type event struct {
 	hash      string
 	eventType string
 
+	suggestion *blockSuggestion
+
 	state eventState
+
+	// stateKey is the key under which this event's state is saved when a StateStore is configured
+	stateKey string
+
+	// readFileIndex tracks the files uploaded by this handler so files aren't uploaded again when processing actions
+	readFileIndex int
 }
 
 type eventMetadata struct {
@@ -44,10 +53,16 @@ type eventMetadata struct {
 }
 
 func (e eventMetadata) User() spanner.User {
+	if e.UserInfo == nil {
+		return nil
+	}
 	return e.UserInfo
 }
 
 func (e eventMetadata) Channel() spanner.Channel {
+	if e.ChannelInfo == nil {
+		return nil
+	}
 	return e.ChannelInfo
 }
 
@@ -56,11 +71,21 @@ type eventState struct {
 
 	*MessageSender `json:"ms"`
 
-	Metadata     eventMetadata    `json:"metadata"`
-	Connected    bool             `json:"connected"`
-	SlashCommand *slashCommand    `json:"slash_command"`
-	Message      *receivedMessage `json:"message"`
-	Custom       *customEvent     `json:"customEvent"`
+	Metadata        eventMetadata    `json:"metadata"`
+	Connected       bool             `json:"connected"`
+	SlashCommand    *slashCommand    `json:"slash_command"`
+	Message         *receivedMessage `json:"message"`
+	AppMention      *appMention      `json:"app_mention"`
+	ReactionAdded   *reaction        `json:"reaction_added"`
+	ReactionRemoved *reaction        `json:"reaction_removed"`
+	Custom          *customEvent     `json:"customEvent"`
+	HomeTab         *homeTab         `json:"home_tab"`
+	GlobalShortcut  *shortcut        `json:"global_shortcut"`
+	MessageShortcut *shortcut        `json:"message_shortcut"`
+	FilesUploaded   []int            `json:"files_uploaded,omitempty"`
+
+	// CreatedChannels maps the placeholder IDs of channels created by this event to their IDs
+	CreatedChannels map[string]string `json:"created_channels,omitempty"`
 }
 
 func (e *event) ReceiveConnected() bool {
@@ -73,6 +98,95 @@ func (e *event) JoinChannel(channelID string) {
 	})
 }
 
+func (e *event) CreateChannel(name string, private bool) spanner.ChannelHandle {
+	// Channel names are unique, so the placeholder is stable even if other channels
+	// are only created for some interactions
+	action := &createChannelAction{
+		placeholder: fmt.Sprintf("new-channel-%v", name),
+		name:        name,
+		private:     private,
+	}
+	// Channels created by earlier events are not created again
+	if id, ok := e.state.CreatedChannels[action.placeholder]; ok {
+		action.channelID = id
+		return action
+	}
+	e.state.actionQueue.enqueue(action)
+	return action
+}
+
+func (e *event) SetChannelTopic(channelID string, topic string) spanner.HasError {
+	return e.enqueueChannelAction(&channelAction{
+		actionType: setChannelTopicAction,
+		channelID:  channelID,
+		value:      topic,
+	})
+}
+
+func (e *event) SetChannelPurpose(channelID string, purpose string) spanner.HasError {
+	return e.enqueueChannelAction(&channelAction{
+		actionType: setChannelPurposeAction,
+		channelID:  channelID,
+		value:      purpose,
+	})
+}
+
+func (e *event) InviteToChannel(channelID string, userIDs ...string) spanner.HasError {
+	return e.enqueueChannelAction(&channelAction{
+		actionType: inviteToChannelAction,
+		channelID:  channelID,
+		userIDs:    userIDs,
+	})
+}
+
+func (e *event) KickFromChannel(channelID string, userIDs ...string) spanner.HasError {
+	return e.enqueueChannelAction(&channelAction{
+		actionType: kickFromChannelAction,
+		channelID:  channelID,
+		userIDs:    userIDs,
+	})
+}
+
+func (e *event) ArchiveChannel(channelID string) spanner.HasError {
+	return e.enqueueChannelAction(&channelAction{
+		actionType: archiveChannelAction,
+		channelID:  channelID,
+	})
+}
+
+func (e *event) LeaveChannel(channelID string) spanner.HasError {
+	return e.enqueueChannelAction(&channelAction{
+		actionType: leaveChannelAction,
+		channelID:  channelID,
+	})
+}
+
+func (e *event) enqueueChannelAction(action *channelAction) spanner.HasError {
+	e.state.actionQueue.enqueue(action)
+	return action
+}
+
+func (e *event) AddReaction(emoji string, channelID string, timestamp string) spanner.HasError {
+	action := &reactionAction{
+		emoji:     emoji,
+		channelID: channelID,
+		timestamp: timestamp,
+	}
+	e.state.actionQueue.enqueue(action)
+	return action
+}
+
+func (e *event) RemoveReaction(emoji string, channelID string, timestamp string) spanner.HasError {
+	action := &reactionAction{
+		emoji:     emoji,
+		channelID: channelID,
+		timestamp: timestamp,
+		remove:    true,
+	}
+	e.state.actionQueue.enqueue(action)
+	return action
+}
+
 func (e *event) ReceiveCustomEvent() spanner.CustomEvent {
 	if e.state.Custom != nil {
 		return e.state.Custom
@@ -87,6 +201,47 @@ func (e *event) ReceiveMessage() spanner.ReceivedMessage {
 	return nil
 }
 
+func (e *event) ReceiveGlobalShortcut(callbackID string) spanner.GlobalShortcut {
+	if e.state.GlobalShortcut == nil {
+		return nil
+	}
+	if e.state.GlobalShortcut.CallbackID != callbackID {
+		return nil
+	}
+	return e.state.GlobalShortcut
+}
+
+func (e *event) ReceiveMessageShortcut(callbackID string) spanner.MessageShortcut {
+	if e.state.MessageShortcut == nil {
+		return nil
+	}
+	if e.state.MessageShortcut.CallbackID != callbackID {
+		return nil
+	}
+	return e.state.MessageShortcut
+}
+
+func (e *event) ReceiveAppMention() spanner.AppMention {
+	if e.state.AppMention != nil {
+		return e.state.AppMention
+	}
+	return nil
+}
+
+func (e *event) ReceiveReactionAdded() spanner.Reaction {
+	if e.state.ReactionAdded != nil {
+		return e.state.ReactionAdded
+	}
+	return nil
+}
+
+func (e *event) ReceiveReactionRemoved() spanner.Reaction {
+	if e.state.ReactionRemoved != nil {
+		return e.state.ReactionRemoved
+	}
+	return nil
+}
+
 func (e *event) ReceiveSlashCommand(command string) spanner.SlashCommand {
 	if e.state.SlashCommand == nil {
 		return nil
@@ -97,16 +252,70 @@ func (e *event) ReceiveSlashCommand(command string) spanner.SlashCommand {
 	return e.state.SlashCommand
 }
 
+func (e *event) ReceiveAppHomeOpened() spanner.HomeTab {
+	if e.state.HomeTab == nil {
+		return nil
+	}
+	e.state.HomeTab.enqueue()
+	return e.state.HomeTab
+}
+
 func (e *event) SendMessage(channelID string) spanner.Message {
 	return e.state.SendMessage(channelID)
 }
 
+func (e *event) SendDirectMessage(userID string) spanner.Message {
+	return e.state.SendDirectMessage(userID)
+}
+
+func (e *event) UploadFile(channelID string, name string, content io.Reader) spanner.HasError {
+	action := &uploadFileAction{
+		channelID: channelID,
+		name:      name,
+		content:   content,
+		fileIndex: e.readFileIndex,
+	}
+	e.readFileIndex++
+
+	// Files uploaded by earlier events are not uploaded again
+	for _, uploaded := range e.state.FilesUploaded {
+		if uploaded == action.fileIndex {
+			return action
+		}
+	}
+	e.state.actionQueue.enqueue(action)
+	return action
+}
+
+func (e *event) SendEphemeral(userID string, channelID string) spanner.Message {
+	return e.state.SendEphemeral(userID, channelID)
+}
+
 func (e *event) finishEvent(
 	ctx context.Context,
 	actionInterceptor spanner.ActionInterceptor,
 	req request,
 ) error {
-	return finishEvent(ctx, actionInterceptor, req, e.state.actionQueue, true)
+	if e.state.MessageSender != nil {
+		e.state.MessageSender.prepareMessages()
+	}
+	if e.suggestion == nil && e.state.MessageSender != nil {
+		e.state.actionQueue.enqueue(&refreshMessagesAction{
+			sender: e.state.MessageSender,
+		})
+	}
+	return finishEvent(ctx, actionInterceptor, req, e.pendingActions(), true)
+}
+
+// pendingActions returns the actions to perform when finishing this event.
+// When loading options for an external select, only the options are returned.
+func (e *event) pendingActions() *actionQueue {
+	if e.suggestion != nil {
+		q := &actionQueue{}
+		q.enqueue(e.suggestion)
+		return q
+	}
+	return e.state.actionQueue
 }
 
 func finishEvent(
@@ -168,42 +377,80 @@ func finishEvent(
 
 type eventPopulation struct {
 	actionQueue *actionQueue
+	client      socketClient
+	sender      *MessageSender
 
 	interactionCallbackEvent slack.InteractionCallback
 	interaction              slack.InteractionType
+	suggestion               *blockSuggestion
 	messageIndex             string
 	interactionDepth         int
 }
 
-func parseCombinedEvent(ctx context.Context, client socketClient, ce combinedEvent) *event {
+// restoreState restores the state of the event that created a message or view.
+// If the state cannot be loaded, for example because it has expired from the StateStore,
+// the error is logged and the event is acknowledged as a stale interaction without calling the handler.
+func (e *event) restoreState(ctx context.Context, store StateStore, metadata string) bool {
+	state, err := loadState(ctx, store, []byte(metadata))
+	if err == nil {
+		err = json.Unmarshal(state, &e.state)
+	}
+	if err != nil {
+		log.Printf("handling stale interaction: %v", err)
+		e.state = newEvent().state
+		e.eventType = "stale_interaction"
+		return false
+	}
+	return true
+}
+
+func parseCombinedEvent(ctx context.Context, client socketClient, store StateStore, ce combinedEvent) *event {
 	out := newEvent()
 
 	defer func() {
 		// Set clients in metadata
-		if out.state.Metadata.ChannelInfo != nil {
-			out.state.Metadata.ChannelInfo.client = client
-		}
-		if out.state.Metadata.UserInfo != nil {
-			out.state.Metadata.UserInfo.client = client
+		bind := func(m *eventMetadata) {
+			if m.ChannelInfo != nil {
+				m.ChannelInfo.client = client
+			}
+			if m.UserInfo != nil {
+				m.UserInfo.client = client
+				m.UserInfo.sender = out.state.MessageSender
+			}
 		}
 
+		bind(&out.state.Metadata)
+
 		// TODO: There's probably a better way to do this
 		if out.state.SlashCommand != nil {
-			if out.state.SlashCommand.eventMetadata.ChannelInfo != nil {
-				out.state.SlashCommand.eventMetadata.ChannelInfo.client = client
-			}
-			if out.state.SlashCommand.eventMetadata.UserInfo != nil {
-				out.state.SlashCommand.eventMetadata.UserInfo.client = client
-			}
+			bind(&out.state.SlashCommand.eventMetadata)
 		}
 		if out.state.Message != nil {
-			if out.state.Message.eventMetadata.ChannelInfo != nil {
-				out.state.Message.eventMetadata.ChannelInfo.client = client
+			out.state.Message.sender = out.state.MessageSender
+			bind(&out.state.Message.eventMetadata)
+		}
+		if out.state.AppMention != nil {
+			out.state.AppMention.sender = out.state.MessageSender
+			bind(&out.state.AppMention.eventMetadata)
+		}
+		for _, r := range []*reaction{out.state.ReactionAdded, out.state.ReactionRemoved} {
+			if r != nil {
+				bind(&r.eventMetadata)
 			}
-			if out.state.Message.eventMetadata.UserInfo != nil {
-				out.state.Message.eventMetadata.UserInfo.client = client
+		}
+		for _, s := range []*shortcut{out.state.GlobalShortcut, out.state.MessageShortcut} {
+			if s == nil {
+				continue
+			}
+			bind(&s.eventMetadata)
+			if s.MessageInternal != nil {
+				s.MessageInternal.sender = out.state.MessageSender
+				bind(&s.MessageInternal.eventMetadata)
 			}
 		}
+		if out.state.HomeTab != nil {
+			bind(&out.state.HomeTab.eventMetadata)
+		}
 	}()
 
 	if ce.customEvent != nil {
@@ -232,9 +479,9 @@ func parseCombinedEvent(ctx context.Context, client socketClient, ce combinedEve
 			return out
 		}
 
+		// Slash commands only include the channel name, so other details are loaded when needed
 		out.state.Metadata.ChannelInfo = &channel{
 			client:       client,
-			Loaded:       true,
 			IDInternal:   cmd.ChannelID,
 			NameInternal: cmd.ChannelName,
 		}
@@ -277,8 +524,60 @@ func parseCombinedEvent(ctx context.Context, client socketClient, ce combinedEve
 				}
 
 				out.state.Message = &receivedMessage{
+					eventMetadata:     out.state.Metadata,
+					TextInternal:      ev.Text,
+					TimestampInternal: ev.TimeStamp,
+					ThreadTSInternal:  ev.ThreadTimeStamp,
+				}
+			case *slackevents.AppMentionEvent:
+				out.eventType = "app_mention"
+				out.state.Metadata.ChannelInfo = &channel{
+					client:     client,
+					IDInternal: ev.Channel,
+				}
+				out.state.Metadata.UserInfo = &user{
+					client:     client,
+					IDInternal: ev.User,
+				}
+
+				callback, _ := eventsAPIEvent.Data.(*slackevents.EventsAPICallbackEvent)
+				out.state.AppMention = &appMention{
+					receivedMessage: receivedMessage{
+						eventMetadata:     out.state.Metadata,
+						TextInternal:      stripMention(ev.Text, botUserIDs(ce.ev, callback)),
+						TimestampInternal: ev.TimeStamp,
+						ThreadTSInternal:  ev.ThreadTimeStamp,
+					},
+				}
+			case *slackevents.ReactionAddedEvent:
+				out.eventType = "reaction_added"
+				out.state.ReactionAdded = newReaction(client, ev.User, ev.Reaction, ev.Item)
+				out.state.Metadata = out.state.ReactionAdded.eventMetadata
+			case *slackevents.ReactionRemovedEvent:
+				out.eventType = "reaction_removed"
+				out.state.ReactionRemoved = newReaction(client, ev.User, ev.Reaction, ev.Item)
+				out.state.Metadata = out.state.ReactionRemoved.eventMetadata
+			case *slackevents.AppHomeOpenedEvent:
+				if ev.Tab != "home" {
+					return out
+				}
+				out.eventType = "app_home_opened"
+				out.state.Metadata.ChannelInfo = &channel{
+					client:     client,
+					IDInternal: ev.Channel,
+				}
+				out.state.Metadata.UserInfo = &user{
+					client:     client,
+					IDInternal: ev.User,
+				}
+
+				out.state.HomeTab = &homeTab{
+					actionQueue:   out.state.actionQueue,
 					eventMetadata: out.state.Metadata,
-					TextInternal:  ev.Text,
+					Blocks: &Blocks{
+						client: client,
+					},
+					hash: ev.View.Hash,
 				}
 			}
 			return out
@@ -294,36 +593,99 @@ func parseCombinedEvent(ctx context.Context, client socketClient, ce combinedEve
 
 		out.hash = interactionCallbackEvent.Hash
 
+		if interactionCallbackEvent.Type == slack.InteractionTypeBlockSuggestion {
+			out.suggestion = &blockSuggestion{
+				blockID: interactionCallbackEvent.BlockID,
+				query:   interactionCallbackEvent.Value,
+			}
+		}
+
+		if interactionCallbackEvent.Type == slack.InteractionTypeShortcut {
+			out.eventType = "global_shortcut"
+			out.state.Metadata.UserInfo = &user{
+				client:       client,
+				IDInternal:   interactionCallbackEvent.User.ID,
+				NameInternal: interactionCallbackEvent.User.Name,
+			}
+			out.state.GlobalShortcut = newShortcut(out.state.actionQueue, out.state.Metadata, interactionCallbackEvent)
+			return out
+		}
+
+		if interactionCallbackEvent.Type == slack.InteractionTypeMessageAction {
+			out.eventType = "message_shortcut"
+			out.state.Metadata.ChannelInfo = &channel{
+				client:       client,
+				IDInternal:   interactionCallbackEvent.Channel.ID,
+				NameInternal: interactionCallbackEvent.Channel.Name,
+			}
+			out.state.Metadata.UserInfo = &user{
+				client:       client,
+				IDInternal:   interactionCallbackEvent.User.ID,
+				NameInternal: interactionCallbackEvent.User.Name,
+			}
+			out.state.MessageShortcut = newShortcut(out.state.actionQueue, out.state.Metadata, interactionCallbackEvent)
+
+			target := interactionCallbackEvent.Message
+			out.state.MessageShortcut.MessageInternal = &receivedMessage{
+				eventMetadata: eventMetadata{
+					ChannelInfo: out.state.Metadata.ChannelInfo,
+					UserInfo: &user{
+						client:     client,
+						IDInternal: target.User,
+					},
+				},
+				TextInternal:      target.Text,
+				TimestampInternal: target.Timestamp,
+				ThreadTSInternal:  target.ThreadTimestamp,
+			}
+			return out
+		}
+
 		if metadata := interactionCallbackEvent.View.PrivateMetadata; metadata != "" {
 			out.eventType = "view_submission"
-			err := json.Unmarshal([]byte(metadata), &out.state)
-			if err != nil {
-				panic(err)
+			if interactionCallbackEvent.View.Type == slack.VTHomeTab {
+				out.eventType = "home_tab_action"
+			}
+			if !out.restoreState(ctx, store, metadata) {
+				return out
+			}
+			p := eventPopulation{
+				actionQueue:              out.state.actionQueue,
+				client:                   client,
+				sender:                   out.state.MessageSender,
+				interactionCallbackEvent: interactionCallbackEvent,
+				interaction:              interactionCallbackEvent.Type,
+				suggestion:               out.suggestion,
+				messageIndex:             "",
 			}
 			if out.state.SlashCommand != nil {
-				out.state.SlashCommand.populateEvent(
-					ctx,
-					eventPopulation{
-						actionQueue:              out.state.actionQueue,
-						interactionCallbackEvent: interactionCallbackEvent,
-						interaction:              interactionCallbackEvent.Type,
-						messageIndex:             "",
-					},
-					0,
-				)
+				out.state.SlashCommand.populateEvent(ctx, p, 0)
+			}
+			if out.state.HomeTab != nil {
+				out.state.HomeTab.populateEvent(ctx, p, 0)
+			}
+			if out.state.GlobalShortcut != nil {
+				out.state.GlobalShortcut.populateEvent(ctx, p, 0)
+			}
+			if out.state.MessageShortcut != nil {
+				out.state.MessageShortcut.populateEvent(ctx, p, 0)
 			}
 
-		} else if eventMeta := interactionCallbackEvent.Message.Metadata; eventMeta.EventType == "bot_message" {
+		} else if messageIndex, metadata, ok := messageActionState(interactionCallbackEvent); ok {
 			out.eventType = "message_action"
-			messageIndex := eventMeta.EventPayload["message_index"].(string)
-			err := json.Unmarshal([]byte(eventMeta.EventPayload["metadata"].(string)), &out.state)
-			if err != nil {
-				panic(err)
+			if interactionCallbackEvent.Container.IsEphemeral {
+				out.eventType = "ephemeral_message_action"
+			}
+			if !out.restoreState(ctx, store, metadata) {
+				return out
 			}
 			p := eventPopulation{
 				actionQueue:              out.state.actionQueue,
+				client:                   client,
+				sender:                   out.state.MessageSender,
 				interactionCallbackEvent: interactionCallbackEvent,
 				interaction:              interactionCallbackEvent.Type,
+				suggestion:               out.suggestion,
 				messageIndex:             messageIndex,
 			}
 
@@ -339,7 +701,17 @@ func parseCombinedEvent(ctx context.Context, client socketClient, ce combinedEve
 					panic(err)
 				}
 			}
+			if out.state.AppMention != nil {
+				err := out.state.AppMention.populateEvent(ctx, p, 0)
+				if err != nil {
+					panic(err)
+				}
+			}
+
+		}
 
+		if out.suggestion != nil {
+			out.eventType = "block_suggestion"
 		}
 
 		return out
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestExternalSelectLoadsOptions(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if msg := ev.ReceiveMessage(); msg != nil && msg.Text() == "hello" {
+				reply := ev.SendMessage(msg.Channel().ID())
+				reply.ExternalSelect("Service", func(ctx context.Context, query string) []spanner.Option {
+					var out []spanner.Option
+					for _, service := range []string{"api", "auth", "billing"} {
+						if strings.HasPrefix(service, query) {
+							out = append(out, spanner.Option{Label: service, Value: service})
+						}
+					}
+					return out
+				}, spanner.WithID("service"))
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(
+		slackevents.MessageEvent{
+			Text:    "hello",
+			Channel: "ABC123",
+			User:    "DEF456",
+		},
+	))
+
+	if len(client.messagesSent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(client.messagesSent))
+	}
+	msg := client.messagesSent[0]
+	client.messagesSent = nil
+	client.acked = nil
+
+	client.SendEventToApp(blockSuggestionEvent(msg.metadata, "service", "a"))
+
+	if len(client.messagesSent) != 0 || len(client.messagesUpdated) != 0 {
+		t.Errorf("expected no messages to be sent or updated when loading options")
+	}
+	if len(client.acked) != 1 {
+		t.Fatalf("expected one acknowledgement, got %d", len(client.acked))
+	}
+	response, ok := client.acked[0].(slack.OptionsResponse)
+	if !ok {
+		t.Fatalf("expected options response, got %T", client.acked[0])
+	}
+	if len(response.Options) != 2 || response.Options[0].Value != "api" || response.Options[1].Value != "auth" {
+		t.Errorf("unexpected options: %+v", response.Options)
+	}
+}
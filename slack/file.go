@@ -0,0 +1,84 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+var _ action = &uploadFileAction{}
+
+type uploadFileAction struct {
+	channelID string
+	name      string
+	content   io.Reader
+
+	// message is set when the file is attached to a message, and
+	// provides the channel and thread once the message is sent.
+	message *message
+
+	// fileIndex is the order in which the file was uploaded by the handler, so files
+	// that were uploaded successfully aren't uploaded again by later events.
+	fileIndex int
+
+	errFunc spanner.ErrorFunc
+}
+
+func (u *uploadFileAction) ErrorFunc(ef spanner.ErrorFunc) {
+	u.errFunc = ef
+}
+
+func (u *uploadFileAction) getErrorFunc() spanner.ErrorFunc {
+	return u.errFunc
+}
+
+// Data implements action.
+func (u *uploadFileAction) Data() interface{} {
+	// TODO: This should be more well-defined
+	return map[string]interface{}{
+		"channel_id": u.channelID,
+		"name":       u.name,
+	}
+}
+
+// Type implements action.
+func (*uploadFileAction) Type() string {
+	return "upload_file"
+}
+
+// exec implements action.
+func (u *uploadFileAction) exec(ctx context.Context, req request) (interface{}, error) {
+	params := slack.UploadFileV2Parameters{
+		Filename: u.name,
+		Title:    u.name,
+		Channel:  req.channelID(u.channelID),
+	}
+	if m := u.message; m != nil {
+		if m.Ephemeral {
+			return nil, fmt.Errorf("uploading file: files cannot be attached to ephemeral messages")
+		}
+		params.Channel = m.ChannelID
+		params.ThreadTimestamp = m.ThreadTS
+	}
+
+	// The size of the file must be known before it is uploaded
+	content, err := io.ReadAll(u.content)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	params.Reader = bytes.NewReader(content)
+	params.FileSize = len(content)
+
+	_, err = req.client.UploadFileV2Context(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("uploading file: %w", renderSlackError(err))
+	}
+	if u.message == nil {
+		req.es.state.FilesUploaded = append(req.es.state.FilesUploaded, u.fileIndex)
+	}
+	return nil, nil
+}
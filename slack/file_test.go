@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestUploadFiles(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var uploadErr error
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if msg := ev.ReceiveMessage(); msg != nil && msg.Text() == "report" {
+				ev.UploadFile(msg.Channel().ID(), "report.csv", strings.NewReader("a,b\n1,2\n"))
+
+				reply := msg.Reply()
+				reply.PlainText("Here's the diff")
+				reply.Attach("changes.diff", strings.NewReader("-old\n+new\n"))
+				reply.Button("Refresh")
+
+				ev.UploadFile("invalid_channel", "missing.txt", strings.NewReader("")).ErrorFunc(func(ctx context.Context, ev spanner.ErrorEvent) {
+					uploadErr = ev.ReceiveError()
+				})
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:      "report",
+		Channel:   "ABC123",
+		User:      "DEF456",
+		TimeStamp: "1699999999.000001",
+	}))
+
+	if len(client.filesUploaded) != 2 {
+		t.Fatalf("expected two files to be uploaded, got %d", len(client.filesUploaded))
+	}
+	if got := client.filesUploaded[0]; got.channelID != "ABC123" || got.name != "report.csv" || got.content != "a,b\n1,2\n" {
+		t.Errorf("unexpected upload: %+v", got)
+	}
+	if got := client.filesUploaded[1]; got.channelID != "ABC123" || got.threadTS != "1699999999.000001" || got.name != "changes.diff" {
+		t.Errorf("expected attachment in the reply's thread, got %+v", got)
+	}
+	if uploadErr == nil {
+		t.Errorf("expected an error uploading to an invalid channel")
+	}
+
+	// Interacting with the reply should not upload any files again, but retries the failed upload
+	client.filesUploaded = nil
+	uploadErr = nil
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		"1700000000.000001",
+		client.messagesSent[0].metadata,
+		buttonClick("input-0", "Refresh"),
+		nil,
+	))
+	if len(client.filesUploaded) != 0 {
+		t.Errorf("expected no files to be uploaded again, got %+v", client.filesUploaded)
+	}
+	if uploadErr == nil {
+		t.Errorf("expected the failed upload to be retried")
+	}
+}
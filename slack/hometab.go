@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+var _ spanner.HomeTab = &homeTab{}
+var _ eventPopulator = &homeTab{}
+var _ action = &homeTab{}
+
+type homeTab struct {
+	actionQueue *actionQueue
+
+	eventMetadata
+	*Blocks `json:"blocks"`
+
+	hash   string
+	queued bool
+
+	errFunc spanner.ErrorFunc
+}
+
+func (h *homeTab) ErrorFunc(ef spanner.ErrorFunc) {
+	h.errFunc = ef
+}
+
+func (h *homeTab) getErrorFunc() spanner.ErrorFunc {
+	return h.errFunc
+}
+
+// enqueue ensures the Home tab is published when the event is finished.
+func (h *homeTab) enqueue() {
+	if h.queued {
+		return
+	}
+	h.queued = true
+	h.actionQueue.enqueue(h)
+}
+
+func (h *homeTab) render() slack.HomeTabViewRequest {
+	return slack.HomeTabViewRequest{
+		Type: slack.VTHomeTab,
+		Blocks: slack.Blocks{
+			BlockSet: h.blocks,
+		},
+	}
+}
+
+func (h *homeTab) exec(ctx context.Context, req request) (interface{}, error) {
+	view := h.render()
+	metadata, err := req.Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rendering view: %w", err)
+	}
+	view.PrivateMetadata = string(metadata)
+
+	_, err = req.client.PublishViewContext(ctx, h.UserInfo.ID(), view, h.hash)
+	if err != nil {
+		return nil, fmt.Errorf("publishing home tab: %w", renderSlackError(err))
+	}
+	return nil, nil
+}
+
+func (h *homeTab) populateEvent(ctx context.Context, p eventPopulation, depth int) error {
+	if h.Blocks == nil {
+		h.Blocks = &Blocks{}
+	}
+
+	h.actionQueue = p.actionQueue
+	h.hash = p.interactionCallbackEvent.View.Hash
+	h.BlockStates = blockActionToState(p)
+	h.suggestion = p.suggestion
+	h.client = p.client
+	h.sender = p.sender
+
+	if p.interaction == slack.InteractionTypeBlockActions {
+		h.enqueue()
+	}
+
+	return nil
+}
+
+func (*homeTab) Type() string {
+	return "home_tab"
+}
+
+func (h *homeTab) Data() interface{} {
+	// TODO: This should be more well-defined
+	return map[string]interface{}{
+		"user_id": h.UserInfo.ID(),
+		"blocks":  h.blocks,
+	}
+}
@@ -0,0 +1,70 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestHomeTab(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var clicks int
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if home := ev.ReceiveAppHomeOpened(); home != nil {
+				home.Header(fmt.Sprintf("Welcome <@%v>", home.User().ID()))
+				if home.Button("Refresh") {
+					clicks++
+				}
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(appHomeOpenedEvent("U123"))
+
+	if len(client.viewsPublished) != 1 {
+		t.Fatalf("expected one view to be published, got %d", len(client.viewsPublished))
+	}
+	published := client.viewsPublished[0]
+	if published.userID != "U123" {
+		t.Errorf("expected home tab to be published for U123, got %q", published.userID)
+	}
+	if len(published.view.Blocks.BlockSet) != 2 {
+		t.Errorf("expected two blocks, got %d", len(published.view.Blocks.BlockSet))
+	}
+
+	client.SendEventToApp(viewInteractionEvent(
+		slack.View{
+			Type:            slack.VTHomeTab,
+			Hash:            "hash",
+			PrivateMetadata: published.view.PrivateMetadata,
+		},
+		slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{
+				{
+					Type:    "button",
+					BlockID: "input-0",
+					Text:    slack.TextBlockObject{Text: "Refresh"},
+				},
+			},
+		},
+	))
+
+	if clicks != 1 {
+		t.Errorf("expected button to be clicked once, got %d", clicks)
+	}
+	if len(client.viewsPublished) != 2 {
+		t.Fatalf("expected home tab to be published again, got %d", len(client.viewsPublished))
+	}
+	if got := client.viewsPublished[1]; got.userID != "U123" || got.hash != "hash" {
+		t.Errorf("unexpected publish: user %q, hash %q", got.userID, got.hash)
+	}
+}
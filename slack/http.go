@@ -0,0 +1,330 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/theothertomelliott/spanner"
+)
+
+const (
+	defaultListenAddr = ":8080"
+
+	// httpAckTimeout is the time allowed for an event to be handled before a
+	// response is sent to Slack. Slack expects a response within 3 seconds.
+	httpAckTimeout = 3 * time.Second
+
+	maxRequestBodySize = 1 << 20
+)
+
+// NewHTTPApp creates a new slack app that receives events over HTTP instead of socket mode.
+//
+// botToken is the token for the bot user, with prefix 'xoxb-'
+// signingSecret is the signing secret for the app, used to verify incoming requests
+//
+// Events API, interactivity and slash command requests are all served from a single
+// endpoint on ListenAddr, so the request URLs for each can be set to the same value.
+// https://api.slack.com/apis/connections/events-api
+func NewHTTPApp(config AppConfig) (spanner.App, error) {
+	if !strings.HasPrefix(config.BotToken, "xoxb-") {
+		return nil, fmt.Errorf("bot token must be the token with prefix 'xoxb-'")
+	}
+	if config.SigningSecret == "" {
+		return nil, fmt.Errorf("signing secret is required")
+	}
+	if config.ListenAddr == "" {
+		config.ListenAddr = defaultListenAddr
+	}
+
+	api := slack.New(
+		config.BotToken,
+		slack.OptionDebug(config.Debug),
+		slack.OptionLog(log.New(os.Stdout, "api: ", log.Lshortfile|log.LstdFlags)),
+	)
+
+	client := newHTTPClient(api, config.SigningSecret, config.ListenAddr)
+
+	return newAppWithClient(client, config, client.events), nil
+}
+
+var _ http.Handler = &httpClient{}
+
+// httpClient receives events from Slack over HTTP and presents them in the
+// same form as events received over socket mode.
+type httpClient struct {
+	*slack.Client
+
+	signingSecret string
+	listenAddr    string
+	ackTimeout    time.Duration
+
+	events chan socketmode.Event
+
+	pendingMtx sync.Mutex
+	pending    map[string]chan interface{}
+	nextID     uint64
+
+	server   *http.Server
+	stopOnce sync.Once
+	stopping chan struct{}
+}
+
+func newHTTPClient(api *slack.Client, signingSecret string, listenAddr string) *httpClient {
+	h := &httpClient{
+		Client:        api,
+		signingSecret: signingSecret,
+		listenAddr:    listenAddr,
+		ackTimeout:    httpAckTimeout,
+		events:        make(chan socketmode.Event),
+		pending:       make(map[string]chan interface{}),
+		stopping:      make(chan struct{}),
+	}
+	h.server = &http.Server{
+		Addr:    listenAddr,
+		Handler: h,
+	}
+	return h
+}
+
+func (h *httpClient) SendMessageWithMetadata(ctx context.Context, channelID string, blocks []slack.Block, metadata slack.SlackMetadata, options ...slack.MsgOption) (string, string, string, error) {
+	options = append([]slack.MsgOption{slack.MsgOptionBlocks(blocks...), slack.MsgOptionMetadata(metadata)}, options...)
+	return h.SendMessageContext(ctx, channelID, options...)
+}
+
+func (h *httpClient) UpdateMessageWithMetadata(ctx context.Context, channelID string, timestamp string, blocks []slack.Block, metadata slack.SlackMetadata) (string, string, string, error) {
+	return h.UpdateMessageContext(ctx, channelID, timestamp, slack.MsgOptionBlocks(blocks...), slack.MsgOptionMetadata(metadata))
+}
+
+// RunContext serves HTTP requests until the context is cancelled.
+// Any requests still open when the context is cancelled are closed, so Shutdown
+// should be called first to finish them.
+func (h *httpClient) RunContext(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		h.server.Close()
+	}()
+
+	err := h.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown implements shutdownClient.
+// New requests are refused so Slack will retry them, and requests for events that
+// are already being handled are answered once they are acknowledged.
+func (h *httpClient) Shutdown(ctx context.Context) error {
+	h.stopOnce.Do(func() {
+		close(h.stopping)
+	})
+	if err := h.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutting down http server: %w", err)
+	}
+	return nil
+}
+
+// Ack completes the HTTP response for the request with the provided payload.
+func (h *httpClient) Ack(req socketmode.Request, payload ...interface{}) {
+	h.pendingMtx.Lock()
+	ack, ok := h.pending[req.EnvelopeID]
+	delete(h.pending, req.EnvelopeID)
+	h.pendingMtx.Unlock()
+
+	if !ok {
+		return
+	}
+
+	var p interface{}
+	if len(payload) > 0 {
+		p = payload[0]
+	}
+	ack <- p
+}
+
+func (h *httpClient) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := h.verifiedBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	evt, challenge, err := parseHTTPEvent(r, body)
+	if errors.Is(err, errSSLCheck) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if challenge != "" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(challenge))
+		return
+	}
+
+	ack := h.register(evt.Request)
+	defer h.Ack(*evt.Request) // Clean up if the event was never acknowledged
+
+	select {
+	case h.events <- evt:
+	case <-h.stopping:
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	case <-r.Context().Done():
+		return
+	}
+
+	select {
+	case payload := <-ack:
+		writeAckPayload(w, payload)
+	case <-time.After(h.ackTimeout):
+		http.Error(w, "event was not acknowledged", http.StatusInternalServerError)
+	case <-r.Context().Done():
+	}
+}
+
+func (h *httpClient) verifiedBody(r *http.Request) ([]byte, error) {
+	verifier, err := slack.NewSecretsVerifier(r.Header, h.signingSecret)
+	if err != nil {
+		return nil, fmt.Errorf("verifying request: %w", err)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("reading request: %w", err)
+	}
+
+	if _, err := verifier.Write(body); err != nil {
+		return nil, fmt.Errorf("verifying request: %w", err)
+	}
+	if err := verifier.Ensure(); err != nil {
+		return nil, fmt.Errorf("verifying request: %w", err)
+	}
+
+	return body, nil
+}
+
+// register creates an envelope ID for a request and returns a channel that will
+// receive the payload when the request is acknowledged.
+func (h *httpClient) register(req *socketmode.Request) chan interface{} {
+	h.pendingMtx.Lock()
+	defer h.pendingMtx.Unlock()
+
+	h.nextID++
+	req.EnvelopeID = fmt.Sprintf("http-%v", h.nextID)
+
+	ack := make(chan interface{}, 1)
+	h.pending[req.EnvelopeID] = ack
+	return ack
+}
+
+// errSSLCheck is returned by parseHTTPEvent for the requests Slack sends to verify the
+// certificate of a slash command URL. These only need a successful response.
+var errSSLCheck = errors.New("ssl check")
+
+// parseHTTPEvent converts a request body into an event matching those received over socket mode.
+// If the request is a url verification request, the challenge is returned instead.
+func parseHTTPEvent(r *http.Request, body []byte) (evt socketmode.Event, challenge string, err error) {
+	req := &socketmode.Request{
+		Payload: body,
+	}
+	if retry := r.Header.Get("X-Slack-Retry-Num"); retry != "" {
+		req.RetryAttempt, _ = strconv.Atoi(retry)
+		req.RetryReason = r.Header.Get("X-Slack-Retry-Reason")
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		eventsAPIEvent, err := slackevents.ParseEvent(body, slackevents.OptionNoVerifyToken())
+		if err != nil {
+			return evt, "", fmt.Errorf("parsing event: %w", err)
+		}
+		if verification, ok := eventsAPIEvent.Data.(*slackevents.EventsAPIURLVerificationEvent); ok {
+			return evt, verification.Challenge, nil
+		}
+		req.Type = socketmode.RequestTypeEventsAPI
+		return socketmode.Event{
+			Type:    socketmode.EventTypeEventsAPI,
+			Data:    eventsAPIEvent,
+			Request: req,
+		}, "", nil
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return evt, "", fmt.Errorf("parsing form: %w", err)
+	}
+
+	if form.Get("ssl_check") == "1" {
+		return evt, "", errSSLCheck
+	}
+
+	if payload := form.Get("payload"); payload != "" {
+		var callback slack.InteractionCallback
+		if err := json.Unmarshal([]byte(payload), &callback); err != nil {
+			return evt, "", fmt.Errorf("parsing interaction: %w", err)
+		}
+		req.Type = socketmode.RequestTypeInteractive
+		return socketmode.Event{
+			Type:    socketmode.EventTypeInteractive,
+			Data:    callback,
+			Request: req,
+		}, "", nil
+	}
+
+	if form.Get("command") != "" {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		cmd, err := slack.SlashCommandParse(r)
+		if err != nil {
+			return evt, "", fmt.Errorf("parsing slash command: %w", err)
+		}
+		req.Type = socketmode.RequestTypeSlashCommands
+		return socketmode.Event{
+			Type:    socketmode.EventTypeSlashCommand,
+			Data:    cmd,
+			Request: req,
+		}, "", nil
+	}
+
+	return evt, "", fmt.Errorf("unrecognized request")
+}
+
+func writeAckPayload(w http.ResponseWriter, payload interface{}) {
+	if payload == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if m, ok := payload.(map[string]interface{}); ok && len(m) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
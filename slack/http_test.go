@@ -0,0 +1,284 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/theothertomelliott/spanner"
+)
+
+const testSigningSecret = "secret"
+
+func signedRequest(t *testing.T, secret string, contentType string, body string) *http.Request {
+	t.Helper()
+
+	timestamp := fmt.Sprint(time.Now().Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("v0:%v:%v", timestamp, body)))
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestHTTPRejectsInvalidSignature(t *testing.T) {
+	client := newHTTPClient(nil, testSigningSecret, "")
+
+	w := httptest.NewRecorder()
+	client.ServeHTTP(w, signedRequest(t, "wrong", "application/json", `{}`))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestHTTPURLVerification(t *testing.T) {
+	client := newHTTPClient(nil, testSigningSecret, "")
+
+	w := httptest.NewRecorder()
+	client.ServeHTTP(w, signedRequest(t, testSigningSecret, "application/json",
+		`{"type":"url_verification","token":"token","challenge":"abc123"}`,
+	))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Body.String() != "abc123" {
+		t.Errorf("expected challenge to be returned, got %q", w.Body.String())
+	}
+}
+
+func TestHTTPSSLCheck(t *testing.T) {
+	client := newHTTPClient(nil, testSigningSecret, "")
+
+	form := url.Values{
+		"ssl_check": {"1"},
+		"token":     {"token"},
+	}
+
+	w := httptest.NewRecorder()
+	client.ServeHTTP(w, signedRequest(t, testSigningSecret, "application/x-www-form-urlencoded", form.Encode()))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestHTTPSlashCommandIsAcknowledged(t *testing.T) {
+	client := newHTTPClient(nil, testSigningSecret, "")
+
+	form := url.Values{
+		"command":    {"/mycommand"},
+		"channel_id": {"ABC123"},
+		"user_id":    {"DEF456"},
+	}
+
+	go func() {
+		evt := <-client.events
+		if evt.Type != socketmode.EventTypeSlashCommand {
+			t.Errorf("expected slash command event, got %v", evt.Type)
+		}
+		cmd, _ := evt.Data.(slack.SlashCommand)
+		if cmd.Command != "/mycommand" {
+			t.Errorf("expected command %q, got %q", "/mycommand", cmd.Command)
+		}
+		client.Ack(*evt.Request, map[string]interface{}{
+			"text": "response",
+		})
+	}()
+
+	w := httptest.NewRecorder()
+	client.ServeHTTP(w, signedRequest(t, testSigningSecret, "application/x-www-form-urlencoded", form.Encode()))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Body.String() != `{"text":"response"}` {
+		t.Errorf("unexpected response body: %q", w.Body.String())
+	}
+}
+
+func TestHTTPMessageEvent(t *testing.T) {
+	client := newHTTPClient(nil, testSigningSecret, "")
+
+	go func() {
+		evt := <-client.events
+		eventsAPIEvent, _ := evt.Data.(slackevents.EventsAPIEvent)
+		msg, ok := eventsAPIEvent.InnerEvent.Data.(*slackevents.MessageEvent)
+		if !ok {
+			t.Errorf("expected a message event, got %T", eventsAPIEvent.InnerEvent.Data)
+		} else if msg.Text != "hello" {
+			t.Errorf("expected text %q, got %q", "hello", msg.Text)
+		}
+		client.Ack(*evt.Request, map[string]interface{}{})
+	}()
+
+	w := httptest.NewRecorder()
+	client.ServeHTTP(w, signedRequest(t, testSigningSecret, "application/json",
+		`{"type":"event_callback","token":"token","event":{"type":"message","channel":"ABC123","user":"DEF456","text":"hello"}}`,
+	))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected empty response body, got %q", w.Body.String())
+	}
+}
+
+func TestHTTPUnacknowledgedEventFails(t *testing.T) {
+	client := newHTTPClient(nil, testSigningSecret, "")
+	client.ackTimeout = 10 * time.Millisecond
+
+	go func() {
+		<-client.events
+	}()
+
+	w := httptest.NewRecorder()
+	client.ServeHTTP(w, signedRequest(t, testSigningSecret, "application/x-www-form-urlencoded",
+		url.Values{"command": {"/mycommand"}}.Encode(),
+	))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestHTTPRefusesEventsWhileShuttingDown(t *testing.T) {
+	client := newHTTPClient(nil, testSigningSecret, "")
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	client.ServeHTTP(w, signedRequest(t, testSigningSecret, "application/x-www-form-urlencoded",
+		url.Values{"command": {"/mycommand"}}.Encode(),
+	))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestHTTPShutdown(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{name: "finishes in-flight requests", timeout: time.Second},
+		{name: "returns errors from the server", timeout: 10 * time.Millisecond},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// Find a free port for the server
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr := l.Addr().String()
+			l.Close()
+
+			client := newHTTPClient(nil, testSigningSecret, addr)
+			testApp := newAppWithClient(client, AppConfig{}, client.events)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			runErr := make(chan error)
+			go func() {
+				runErr <- testApp.Run(func(ctx context.Context, ev spanner.Event) {
+					if ev.ReceiveSlashCommand("/mycommand") != nil {
+						close(started)
+						<-release
+					}
+				})
+			}()
+
+			send := func() (*http.Response, error) {
+				r := signedRequest(t, testSigningSecret, "application/x-www-form-urlencoded",
+					url.Values{"command": {"/mycommand"}}.Encode(),
+				)
+				r.RequestURI = ""
+				r.URL, _ = url.Parse("http://" + addr + "/")
+				return http.DefaultClient.Do(r)
+			}
+
+			inFlight := make(chan int)
+			go func() {
+				for {
+					res, err := send()
+					if err != nil {
+						// The server may not be listening yet
+						time.Sleep(time.Millisecond)
+						continue
+					}
+					res.Body.Close()
+					inFlight <- res.StatusCode
+					return
+				}
+			}()
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+			defer cancel()
+			shutdownErr := make(chan error)
+			go func() {
+				shutdownErr <- testApp.Shutdown(ctx)
+			}()
+
+			// New requests are refused once shutting down
+			deadline := time.Now().Add(time.Second)
+			for {
+				res, err := send()
+				if err != nil {
+					break
+				}
+				res.Body.Close()
+				if time.Now().After(deadline) {
+					t.Fatalf("expected requests to be refused while shutting down")
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			if test.timeout < time.Second {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+			}
+			close(release)
+
+			if test.timeout < time.Second {
+				if err := <-shutdownErr; err == nil {
+					t.Errorf("expected an error shutting down")
+				}
+				if err := <-runErr; !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expected the shutdown error to be returned from run, got %v", err)
+				}
+				return
+			}
+
+			if code := <-inFlight; code != http.StatusOK {
+				t.Errorf("expected in-flight request to succeed, got status %d", code)
+			}
+			if err := <-shutdownErr; err != nil {
+				t.Errorf("unexpected error shutting down: %v", err)
+			}
+			if err := <-runErr; err != nil {
+				t.Errorf("unexpected error from run: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package slack
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/theothertomelliott/spanner"
+)
+
+var _ spanner.AppMention = &appMention{}
+var _ eventPopulator = &appMention{}
+
+type appMention struct {
+	receivedMessage
+}
+
+func (m *appMention) Args() []string {
+	return strings.Fields(m.TextInternal)
+}
+
+// leadingMention matches a user mention at the start of a message, such as "<@U123ABC>" or "<@U123ABC|name>".
+var leadingMention = regexp.MustCompile(`^\s*<@([A-Z0-9]+)(\|[^>]*)?>\s*`)
+
+// stripMention removes the mention of the app from the start of the text of an app_mention event.
+// If the user IDs of the app are known, a leading mention of another user is left in place.
+func stripMention(text string, botUserIDs []string) string {
+	match := leadingMention.FindStringSubmatch(text)
+	if match == nil {
+		return text
+	}
+	if len(botUserIDs) > 0 && !slices.Contains(botUserIDs, match[1]) {
+		return text
+	}
+	return text[len(match[0]):]
+}
+
+// botUserIDs returns the IDs of the app's users that an Events API event was delivered to,
+// from the authorizations in the event payload.
+func botUserIDs(ev *socketmode.Event, callback *slackevents.EventsAPICallbackEvent) []string {
+	var ids []string
+	if ev.Request != nil && len(ev.Request.Payload) > 0 {
+		var payload struct {
+			Authorizations []struct {
+				UserID string `json:"user_id"`
+			} `json:"authorizations"`
+		}
+		if err := json.Unmarshal(ev.Request.Payload, &payload); err == nil {
+			for _, a := range payload.Authorizations {
+				ids = append(ids, a.UserID)
+			}
+		}
+	}
+	// authed_users is deprecated, but may still be sent
+	if len(ids) == 0 && callback != nil {
+		ids = append(ids, callback.AuthedUsers...)
+	}
+	return ids
+}
@@ -0,0 +1,108 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestStripMention(t *testing.T) {
+	var tests = []struct {
+		text     string
+		expected string
+	}{
+		{text: "<@U123ABC> deploy api", expected: "deploy api"},
+		{text: "  <@U123ABC|spanner>   deploy", expected: "deploy"},
+		{text: "<@U123ABC>", expected: ""},
+		{text: "hello <@U123ABC>", expected: "hello <@U123ABC>"},
+	}
+	for _, test := range tests {
+		if got := stripMention(test.text, nil); got != test.expected {
+			t.Errorf("stripMention(%q): expected %q, got %q", test.text, test.expected, got)
+		}
+	}
+
+	// When the app's user ID is known, only a leading mention of the app is removed
+	var botTests = []struct {
+		text     string
+		expected string
+	}{
+		{text: "<@BOT> deploy api", expected: "deploy api"},
+		{text: "<@BOT|spanner> ask <@BOT> later", expected: "ask <@BOT> later"},
+		{text: "<@UOTHER> <@BOT> deploy", expected: "<@UOTHER> <@BOT> deploy"},
+		{text: "hey <@BOT>, ask <@BOT>", expected: "hey <@BOT>, ask <@BOT>"},
+	}
+	for _, test := range botTests {
+		if got := stripMention(test.text, []string{"BOT"}); got != test.expected {
+			t.Errorf("stripMention(%q): expected %q, got %q", test.text, test.expected, got)
+		}
+	}
+}
+
+func TestBotUserIDsFromAuthorizations(t *testing.T) {
+	ev := &socketmode.Event{
+		Request: &socketmode.Request{
+			Payload: []byte(`{"type":"event_callback","authorizations":[{"user_id":"BOT","is_bot":true}]}`),
+		},
+	}
+	if ids := botUserIDs(ev, nil); len(ids) != 1 || ids[0] != "BOT" {
+		t.Errorf("expected bot user from authorizations, got %v", ids)
+	}
+
+	legacy := &slackevents.EventsAPICallbackEvent{AuthedUsers: []string{"LEGACY"}}
+	if ids := botUserIDs(&socketmode.Event{}, legacy); len(ids) != 1 || ids[0] != "LEGACY" {
+		t.Errorf("expected bot user from authed users, got %v", ids)
+	}
+}
+
+func TestReceiveAppMention(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	mention := slackevents.AppMentionEvent{
+		Channel:   "ABC123",
+		User:      "DEF456",
+		Text:      "<@U123ABC> deploy api  production",
+		TimeStamp: "1700000000.000100",
+	}
+	client.SendEventToAppAsync(appMentionEvent(mention))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	testApp.RunContext(ctx, func(ctx context.Context, evt spanner.Event) {
+		defer cancel()
+
+		if evt.ReceiveMessage() != nil {
+			t.Errorf("expected mention not to be received as a message")
+		}
+
+		msg := evt.ReceiveAppMention()
+		if msg == nil {
+			t.Errorf("expected a ReceiveAppMention event")
+			return
+		}
+		if msg.Channel().ID() != mention.Channel {
+			t.Errorf("expected channel id %q, got %q", mention.Channel, msg.Channel().ID())
+		}
+		if msg.User().ID() != mention.User {
+			t.Errorf("expected user id %q, got %q", mention.User, msg.User().ID())
+		}
+		if msg.Text() != "deploy api  production" {
+			t.Errorf("expected mention to be stripped from text, got %q", msg.Text())
+		}
+		if args := msg.Args(); strings.Join(args, ",") != "deploy,api,production" {
+			t.Errorf("unexpected args: %q", args)
+		}
+		msg.Reply().PlainText("Deploying")
+	})
+
+	if len(client.messagesSent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(client.messagesSent))
+	}
+	if got := client.messagesSent[0].threadTS; got != mention.TimeStamp {
+		t.Errorf("expected reply in thread %q, got %q", mention.TimeStamp, got)
+	}
+}
@@ -2,7 +2,10 @@ package slack
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/slack-go/slack"
 	"github.com/theothertomelliott/spanner"
@@ -14,7 +17,11 @@ var _ eventPopulator = &receivedMessage{}
 type receivedMessage struct {
 	eventMetadata
 
-	TextInternal string `json:"text"`
+	TextInternal      string `json:"text"`
+	TimestampInternal string `json:"ts"`
+	ThreadTSInternal  string `json:"thread_ts,omitempty"`
+
+	sender *MessageSender
 }
 
 func (m *receivedMessage) populateEvent(ctx context.Context, p eventPopulation, depth int) error {
@@ -30,12 +37,27 @@ type message struct {
 	*Blocks `json:"blocks"` // This ensures that the value is not nil
 
 	ChannelID           string `json:"channel_id"`
+	ThreadTS            string `json:"thread_ts,omitempty"`
+	Broadcast           bool   `json:"broadcast,omitempty"`
+	Ephemeral           bool   `json:"ephemeral,omitempty"`
+	UserID              string `json:"user_id,omitempty"`
+	DirectUserID        string `json:"direct_user_id,omitempty"`
 	MessageIndex        string `json:"message_index"`
 	EventDepth          int    `json:"event_depth"`
+	TS                  string `json:"ts,omitempty"`
+	BlocksHash          string `json:"blocks_hash,omitempty"`
+	Deleted             bool   `json:"deleted,omitempty"`
+	previousBlocksHash  string
 	currentMessageIndex string
 	currentEventDepth   int
-	actionMessageTS     string
+	responseURL         string
 	unsent              bool
+	queued              bool
+
+	// siblingTS records the timestamps of the other messages when this message's state was
+	// sent by the current event, so it can be refreshed if more messages are sent after it.
+	siblingTS   string
+	stateIsSent bool
 
 	errFunc spanner.ErrorFunc
 }
@@ -56,6 +78,9 @@ func (m *message) Data() interface{} {
 	// TODO: This should be more well-defined
 	return map[string]interface{}{
 		"channel_id": m.ChannelID,
+		"thread_ts":  m.ThreadTS,
+		"ts":         m.TS,
+		"deleted":    m.Deleted,
 		"blocks":     m.blocks,
 	}
 }
@@ -64,38 +89,104 @@ func (m *message) Channel(channelID string) {
 	m.ChannelID = channelID
 }
 
+func (m *message) Delete() {
+	m.Deleted = true
+}
+
+func (m *message) Attach(name string, content io.Reader) spanner.HasError {
+	action := &uploadFileAction{
+		message: m,
+		name:    name,
+		content: content,
+	}
+	// Files are only uploaded when the message is first sent
+	if m.unsent {
+		m.sender.actionQueue.enqueue(action)
+	}
+	return action
+}
+
+// blocksHash returns a hash of the rendered blocks, to detect changes to messages sent by earlier events.
+func (m *message) blocksHash() string {
+	blocks, err := json.Marshal(m.blocks)
+	if err != nil {
+		return ""
+	}
+	return hashstr(string(blocks))
+}
+
 func (m *message) exec(ctx context.Context, req request) (interface{}, error) {
+	m.ChannelID = req.channelID(m.ChannelID)
+
+	if m.Ephemeral {
+		return m.execEphemeral(ctx, req)
+	}
+
+	if m.Deleted {
+		if m.unsent || m.TS == "" {
+			return nil, nil
+		}
+		_, _, err := req.client.DeleteMessageContext(ctx, m.ChannelID, m.TS)
+		if err != nil {
+			return nil, fmt.Errorf("deleting message: %w", renderSlackError(err))
+		}
+		// Clear the timestamp so the message isn't deleted again by later events
+		m.TS = ""
+		return nil, nil
+	}
+
+	isCurrent := m.MessageIndex == m.currentMessageIndex && m.EventDepth == m.currentEventDepth
+	if !m.unsent && !isCurrent && m.BlocksHash == m.previousBlocksHash {
+		// Messages sent by earlier events only need updating if they have changed
+		return nil, nil
+	}
+
+	if m.unsent && m.ChannelID == "" && m.DirectUserID != "" {
+		// Open the direct message channel first so it is included in the state
+		ch, _, _, err := req.client.OpenConversationContext(ctx, &slack.OpenConversationParameters{
+			Users: []string{m.DirectUserID},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("opening direct message: %w", renderSlackError(err))
+		}
+		m.ChannelID = ch.ID
+	}
+
+	metadata, err := req.Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sending message: %w", err)
+	}
+	m.siblingTS = req.es.state.MessageSender.siblingTimestamps(m)
+	m.stateIsSent = true
+
 	if m.unsent {
-		_, _, _, err := req.client.SendMessageWithMetadata(
+		var options []slack.MsgOption
+		if m.ThreadTS != "" {
+			options = append(options, slack.MsgOptionTS(m.ThreadTS))
+			if m.Broadcast {
+				options = append(options, slack.MsgOptionBroadcast())
+			}
+		}
+
+		_, ts, _, err := req.client.SendMessageWithMetadata(
 			ctx,
 			m.ChannelID,
 			m.blocks,
-			slack.SlackMetadata{
-				EventType: "bot_message",
-				EventPayload: map[string]interface{}{
-					"message_index": m.MessageIndex,
-					"event_depth":   m.EventDepth,
-					"metadata":      string(req.Metadata()),
-				},
-			})
+			m.slackMetadata(metadata),
+			options...,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("sending message: %w", renderSlackError(err))
 		}
-
-	} else if m.MessageIndex == m.currentMessageIndex && m.EventDepth == m.currentEventDepth {
+		m.TS = ts
+	} else {
 		_, _, _, err := req.client.UpdateMessageWithMetadata(
 			ctx,
 			m.ChannelID,
-			m.actionMessageTS,
+			m.TS,
 			m.blocks,
-			slack.SlackMetadata{
-				EventType: "bot_message",
-				EventPayload: map[string]interface{}{
-					"message_index": m.MessageIndex,
-					"event_depth":   m.EventDepth,
-					"metadata":      string(req.Metadata()),
-				},
-			})
+			m.slackMetadata(metadata),
+		)
 		if err != nil {
 			return nil, fmt.Errorf("updating message: %w", renderSlackError(err))
 		}
@@ -104,14 +195,47 @@ func (m *message) exec(ctx context.Context, req request) (interface{}, error) {
 	return nil, nil
 }
 
+func (m *message) slackMetadata(metadata []byte) slack.SlackMetadata {
+	return slack.SlackMetadata{
+		EventType: "bot_message",
+		EventPayload: map[string]interface{}{
+			"message_index": m.MessageIndex,
+			"event_depth":   m.EventDepth,
+			"metadata":      string(metadata),
+		},
+	}
+}
+
 func (m *message) populateEvent(ctx context.Context, p eventPopulation, depth int) error {
-	m.BlockStates = blockActionToState(p)
-	m.actionMessageTS = p.interactionCallbackEvent.Message.Timestamp
+	// Requests for options don't include the message state, so keep the
+	// state from when the message was sent
+	if p.suggestion == nil {
+		m.BlockStates = blockActionToState(p)
+	}
+	m.suggestion = p.suggestion
+	m.client = p.client
+	m.sender = p.sender
+	m.responseURL = p.interactionCallbackEvent.ResponseURL
+	if !m.Ephemeral {
+		m.TS = p.interactionCallbackEvent.Message.Timestamp
+	}
 	m.currentEventDepth = p.interactionDepth
 	m.currentMessageIndex = p.messageIndex
 	return nil
 }
 
+// messageActionState returns the index and state of the message an interaction came from.
+// State is read from the message metadata, or from the clicked button for ephemeral messages.
+func messageActionState(ic slack.InteractionCallback) (messageIndex string, metadata string, ok bool) {
+	if eventMeta := ic.Message.Metadata; eventMeta.EventType == "bot_message" {
+		return eventMeta.EventPayload["message_index"].(string), eventMeta.EventPayload["metadata"].(string), true
+	}
+	if ic.Container.IsEphemeral {
+		return ephemeralActionState(ic)
+	}
+	return "", "", false
+}
+
 var _ eventPopulator = &MessageSender{}
 
 type MessageSender struct {
@@ -126,27 +250,113 @@ func (m *receivedMessage) Text() string {
 	return m.TextInternal
 }
 
+func (m *receivedMessage) TS() string {
+	return m.TimestampInternal
+}
+
+func (m *receivedMessage) ThreadTS() string {
+	return m.ThreadTSInternal
+}
+
+func (m *receivedMessage) IsThreadReply() bool {
+	return m.ThreadTSInternal != "" && m.ThreadTSInternal != m.TimestampInternal
+}
+
+func (m *receivedMessage) Reply() spanner.Message {
+	return m.sender.sendMessage(&message{
+		ChannelID: m.ChannelInfo.ID(),
+		ThreadTS:  m.threadRoot(),
+	})
+}
+
+func (m *receivedMessage) ReplyBroadcast() spanner.Message {
+	return m.sender.sendMessage(&message{
+		ChannelID: m.ChannelInfo.ID(),
+		ThreadTS:  m.threadRoot(),
+		Broadcast: true,
+	})
+}
+
+// threadRoot returns the timestamp of the message that replies should be threaded under.
+func (m *receivedMessage) threadRoot() string {
+	if m.ThreadTSInternal != "" {
+		return m.ThreadTSInternal
+	}
+	return m.TimestampInternal
+}
+
+// prepareMessages records the content of each message before any are sent, so the state
+// sent with each message reflects the final content of the others.
+func (m *MessageSender) prepareMessages() {
+	for _, message := range m.Messages {
+		if !message.queued {
+			continue
+		}
+		message.previousBlocksHash = message.BlocksHash
+		message.BlocksHash = message.blocksHash()
+	}
+}
+
+// siblingTimestamps returns the timestamps of the messages sent by this event, other than msg.
+func (m *MessageSender) siblingTimestamps(msg *message) string {
+	var timestamps []string
+	for _, message := range m.Messages {
+		if message != msg {
+			timestamps = append(timestamps, message.TS)
+		}
+	}
+	return strings.Join(timestamps, ",")
+}
+
 func (m *MessageSender) SendMessage(channelID string) spanner.Message {
+	return m.sendMessage(&message{
+		ChannelID: channelID,
+	})
+}
+
+func (m *MessageSender) SendDirectMessage(userID string) spanner.Message {
+	return m.sendMessage(&message{
+		DirectUserID: userID,
+	})
+}
+
+func (m *MessageSender) SendEphemeral(userID string, channelID string) spanner.Message {
+	return m.sendMessage(&message{
+		ChannelID: channelID,
+		UserID:    userID,
+		Ephemeral: true,
+	})
+}
+
+// sendMessage returns the next message sent by this event, creating it from msg
+// if it was not sent by an earlier event.
+func (m *MessageSender) sendMessage(msg *message) spanner.Message {
 	defer func() {
 		m.readMessageIndex++
 	}()
 
 	if m.readMessageIndex < len(m.Messages) {
-		return m.Messages[m.readMessageIndex]
+		existing := m.Messages[m.readMessageIndex]
+		if !existing.queued && existing.TS != "" && !existing.Ephemeral {
+			// Messages sent by earlier events may be updated or deleted
+			existing.queued = true
+			m.actionQueue.enqueue(existing)
+		}
+		return existing
 	}
 
-	message := &message{
-		Blocks:       &Blocks{},
-		MessageIndex: fmt.Sprintf("%v", len(m.Messages)),
-		EventDepth:   m.EventDepth,
-		ChannelID:    channelID,
-		unsent:       true,
+	msg.Blocks = &Blocks{
+		sender: m,
 	}
-	m.Messages = append(m.Messages, message)
+	msg.MessageIndex = fmt.Sprintf("%v", len(m.Messages))
+	msg.EventDepth = m.EventDepth
+	msg.unsent = true
+	msg.queued = true
+	m.Messages = append(m.Messages, msg)
 
-	m.actionQueue.enqueue(message)
+	m.actionQueue.enqueue(msg)
 
-	return message
+	return msg
 }
 
 func (m *MessageSender) populateEvent(ctx context.Context, p eventPopulation, depth int) error {
@@ -154,9 +364,82 @@ func (m *MessageSender) populateEvent(ctx context.Context, p eventPopulation, de
 	for _, message := range m.Messages {
 		if message.MessageIndex == p.messageIndex {
 			m.actionQueue = p.actionQueue
+			message.queued = true
 			p.actionQueue.enqueue(message)
 			return message.populateEvent(ctx, p, depth+1)
 		}
 	}
 	return nil
 }
+
+var _ action = &refreshMessagesAction{}
+
+// refreshMessagesAction ensures that messages sent by an event have the timestamps of their siblings,
+// so that interacting with one message can update the others.
+// It is performed after all other actions for the event.
+type refreshMessagesAction struct {
+	sender *MessageSender
+
+	errFunc spanner.ErrorFunc
+}
+
+func (r *refreshMessagesAction) ErrorFunc(ef spanner.ErrorFunc) {
+	r.errFunc = ef
+}
+
+func (r *refreshMessagesAction) getErrorFunc() spanner.ErrorFunc {
+	return r.errFunc
+}
+
+// Data implements action.
+func (r *refreshMessagesAction) Data() interface{} {
+	// TODO: This should be more well-defined
+	return map[string]interface{}{
+		"messages": len(r.sender.Messages),
+	}
+}
+
+// Type implements action.
+func (*refreshMessagesAction) Type() string {
+	return "refresh_messages"
+}
+
+// exec implements action.
+func (r *refreshMessagesAction) exec(ctx context.Context, req request) (interface{}, error) {
+	if req.store != nil {
+		// Messages share the event's state key, so saving the final state updates them all
+		if req.es.stateKey != "" {
+			if _, err := req.Metadata(ctx); err != nil {
+				return nil, fmt.Errorf("refreshing messages: %w", err)
+			}
+		}
+		return nil, nil
+	}
+
+	for _, m := range r.sender.Messages {
+		// Only messages with interactive elements need the state of their siblings
+		if !m.stateIsSent || m.Deleted || m.Ephemeral || m.TS == "" || len(m.elementIDs) == 0 {
+			continue
+		}
+		if m.siblingTS == r.sender.siblingTimestamps(m) {
+			continue
+		}
+
+		metadata, err := req.Metadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("refreshing message: %w", err)
+		}
+		_, _, _, err = req.client.UpdateMessageWithMetadata(
+			ctx,
+			m.ChannelID,
+			m.TS,
+			m.blocks,
+			m.slackMetadata(metadata),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("refreshing message: %w", renderSlackError(err))
+		}
+		m.siblingTS = r.sender.siblingTimestamps(m)
+	}
+	return nil, nil
+}
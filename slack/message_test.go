@@ -0,0 +1,279 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/theothertomelliott/spanner"
+)
+
+func handlerSiblingMessages(ctx context.Context, ev spanner.Event) {
+	if msg := ev.ReceiveMessage(); msg != nil && msg.Text() == "hello" {
+		summary := ev.SendMessage(msg.Channel().ID())
+
+		controls := ev.SendMessage(msg.Channel().ID())
+		rename := controls.Button("Rename")
+		remove := controls.Button("Delete")
+
+		if rename {
+			summary.PlainText("Renamed summary")
+		} else {
+			summary.PlainText("Summary")
+		}
+		if remove {
+			summary.Delete()
+		}
+	}
+}
+
+func buttonClick(blockID string, text string) slack.ActionCallbacks {
+	return slack.ActionCallbacks{
+		BlockActions: []*slack.BlockAction{
+			{
+				Type:    "button",
+				BlockID: blockID,
+				Text:    slack.TextBlockObject{Text: text},
+			},
+		},
+	}
+}
+
+func TestUpdateAndDeleteSiblingMessages(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(handlerSiblingMessages)
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(
+		slackevents.MessageEvent{
+			Text:    "hello",
+			Channel: "ABC123",
+			User:    "DEF456",
+		},
+	))
+
+	if len(client.messagesSent) != 2 {
+		t.Fatalf("expected two messages to be sent, got %d", len(client.messagesSent))
+	}
+	summaryTS := "1700000000.000001"
+	controlsTS := "1700000000.000002"
+	controls := client.messagesSent[1]
+
+	// The summary has no interactive elements, so doesn't need the timestamp of the controls
+	if len(client.messagesUpdated) != 0 {
+		t.Fatalf("expected no messages to be refreshed, got %+v", client.messagesUpdated)
+	}
+
+	// Renaming should update the summary as well as the message that was clicked
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		controlsTS,
+		controls.metadata,
+		buttonClick("input-0", "Rename"),
+		nil,
+	))
+
+	if len(client.messagesUpdated) != 2 {
+		t.Fatalf("expected two messages to be updated, got %d", len(client.messagesUpdated))
+	}
+	var summaryUpdate *updatedMessage
+	for i, updated := range client.messagesUpdated {
+		if updated.timestamp == summaryTS {
+			summaryUpdate = &client.messagesUpdated[i]
+		}
+		if updated.timestamp == controlsTS {
+			controls = updated.sentMessage
+		}
+	}
+	if summaryUpdate == nil {
+		t.Fatalf("expected summary to be updated, got %+v", client.messagesUpdated)
+	}
+	blocks, _ := json.Marshal(summaryUpdate.blocks)
+	if !strings.Contains(string(blocks), "Renamed summary") {
+		t.Errorf("expected summary to be renamed, got: %v", string(blocks))
+	}
+
+	// Clicking again with the same result should not update the summary
+	client.messagesUpdated = nil
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		controlsTS,
+		controls.metadata,
+		buttonClick("input-0", "Rename"),
+		nil,
+	))
+	if len(client.messagesUpdated) != 1 || client.messagesUpdated[0].timestamp != controlsTS {
+		t.Fatalf("expected only the clicked message to be updated, got %+v", client.messagesUpdated)
+	}
+	controls = client.messagesUpdated[0].sentMessage
+
+	// Deleting should remove the summary
+	client.messagesUpdated = nil
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		controlsTS,
+		controls.metadata,
+		buttonClick("input-1", "Delete"),
+		nil,
+	))
+	if len(client.messagesDeleted) != 1 {
+		t.Fatalf("expected one message to be deleted, got %d", len(client.messagesDeleted))
+	}
+	if deleted := client.messagesDeleted[0]; deleted.channelID != "ABC123" || deleted.timestamp != summaryTS {
+		t.Errorf("unexpected deleted message: %+v", deleted)
+	}
+
+	// Later interactions should not delete the summary again
+	controls = client.messagesUpdated[len(client.messagesUpdated)-1].sentMessage
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		controlsTS,
+		controls.metadata,
+		buttonClick("input-0", "Rename"),
+		nil,
+	))
+	if len(client.messagesDeleted) != 1 {
+		t.Errorf("expected the summary to be deleted once, got %+v", client.messagesDeleted)
+	}
+	for _, updated := range client.messagesUpdated {
+		if updated.timestamp == summaryTS {
+			t.Errorf("expected deleted summary not to be updated, got %+v", updated)
+		}
+	}
+}
+
+func handlerUpdateLaterSibling(ctx context.Context, ev spanner.Event) {
+	if msg := ev.ReceiveMessage(); msg != nil && msg.Text() == "hello" {
+		controls := ev.SendMessage(msg.Channel().ID())
+		archive := controls.Button("Archive")
+
+		status := ev.SendMessage(msg.Channel().ID())
+		if archive {
+			status.PlainText("Archived")
+		} else {
+			status.PlainText("Active")
+		}
+	}
+}
+
+func TestUpdateLaterSiblingMessage(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(handlerUpdateLaterSibling)
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(
+		slackevents.MessageEvent{
+			Text:    "hello",
+			Channel: "ABC123",
+			User:    "DEF456",
+		},
+	))
+
+	if len(client.messagesSent) != 2 {
+		t.Fatalf("expected two messages to be sent, got %d", len(client.messagesSent))
+	}
+	controlsTS := "1700000000.000001"
+	statusTS := "1700000000.000002"
+
+	// The controls are refreshed so they have the timestamp of the status message
+	if len(client.messagesUpdated) != 1 || client.messagesUpdated[0].timestamp != controlsTS {
+		t.Fatalf("expected controls to be refreshed, got %+v", client.messagesUpdated)
+	}
+	controls := client.messagesUpdated[0]
+	client.messagesUpdated = nil
+
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		controlsTS,
+		controls.metadata,
+		buttonClick("input-0", "Archive"),
+		nil,
+	))
+
+	var statusUpdate *updatedMessage
+	for i, updated := range client.messagesUpdated {
+		if updated.timestamp == statusTS {
+			statusUpdate = &client.messagesUpdated[i]
+		}
+	}
+	if statusUpdate == nil {
+		t.Fatalf("expected status to be updated, got %+v", client.messagesUpdated)
+	}
+	blocks, _ := json.Marshal(statusUpdate.blocks)
+	if !strings.Contains(string(blocks), "Archived") {
+		t.Errorf("expected status to be archived, got: %v", string(blocks))
+	}
+	if len(client.messagesSent) != 2 {
+		t.Errorf("expected no new messages, got %d", len(client.messagesSent))
+	}
+}
+
+func TestUpdateLaterSiblingMessageWithStateStore(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := newAppWithClient(
+		client,
+		AppConfig{
+			EventInterceptor: client.EventInterceptor,
+			StateStore:       NewMemoryStateStore(time.Minute),
+		},
+		client.Events,
+	)
+
+	go func() {
+		err := testApp.Run(handlerUpdateLaterSibling)
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(
+		slackevents.MessageEvent{
+			Text:    "hello",
+			Channel: "ABC123",
+			User:    "DEF456",
+		},
+	))
+
+	if len(client.messagesSent) != 2 {
+		t.Fatalf("expected two messages to be sent, got %d", len(client.messagesSent))
+	}
+	// Both messages refer to the same state, so the controls don't need to be refreshed
+	if len(client.messagesUpdated) != 0 {
+		t.Fatalf("expected no messages to be refreshed, got %+v", client.messagesUpdated)
+	}
+
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		"1700000000.000001",
+		client.messagesSent[0].metadata,
+		buttonClick("input-0", "Archive"),
+		nil,
+	))
+
+	var archived bool
+	for _, updated := range client.messagesUpdated {
+		blocks, _ := json.Marshal(updated.blocks)
+		if updated.timestamp == "1700000000.000002" && strings.Contains(string(blocks), "Archived") {
+			archived = true
+		}
+	}
+	if !archived {
+		t.Errorf("expected status to be archived, got %+v", client.messagesUpdated)
+	}
+}
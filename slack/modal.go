@@ -20,6 +20,7 @@ type modal struct {
 	Title      string           `json:"title"`
 	Submission *modalSubmission `json:"submission"`
 	HasParent  bool             `json:"has_parent"`
+	Replaces   bool             `json:"replaces"`
 	ChannelID  string           `json:"channel_id"`
 
 	ViewID         string `json:"view_id"`
@@ -97,7 +98,11 @@ func (m *modal) exec(ctx context.Context, req request) (interface{}, error) {
 	var err error
 
 	modal := m.render()
-	modal.PrivateMetadata = string(req.Metadata())
+	metadata, err := req.Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rendering view: %w", err)
+	}
+	modal.PrivateMetadata = string(metadata)
 
 	var payload interface{} = map[string]interface{}{}
 
@@ -108,6 +113,8 @@ func (m *modal) exec(ctx context.Context, req request) (interface{}, error) {
 			if err != nil {
 				return nil, fmt.Errorf("opening view: %w", renderSlackError(err))
 			}
+		} else if m.Replaces {
+			payload = slack.NewUpdateViewSubmissionResponse(modal)
 		} else {
 			payload = slack.NewPushViewSubmissionResponse(modal)
 		}
@@ -141,6 +148,9 @@ func (m *modal) populateEvent(ctx context.Context, p eventPopulation, depth int)
 	m.ViewExternalID = p.interactionCallbackEvent.View.ExternalID
 	m.ViewID = p.interactionCallbackEvent.View.ID
 	m.BlockStates = blockActionToState(p)
+	m.suggestion = p.suggestion
+	m.client = p.client
+	m.sender = p.sender
 
 	if p.interaction == slack.InteractionTypeBlockActions {
 		m.update = modalUpdateAction
@@ -182,9 +192,12 @@ var _ eventPopulator = &modalSubmission{}
 type modalSubmission struct {
 	actionQueue *actionQueue
 
-	NextModal *modal `json:"next_modal"`
+	NextModal    *modal `json:"next_modal"`
+	UpdatedModal *modal `json:"updated_modal"`
 
 	parent *modal
+	errors map[string]string
+	closed bool
 
 	errFunc spanner.ErrorFunc
 }
@@ -212,7 +225,56 @@ func (m *modalSubmission) PushModal(title string) spanner.Modal {
 	return m.NextModal
 }
 
+func (m *modalSubmission) UpdateModal(title string) spanner.Modal {
+	if m.UpdatedModal != nil {
+		return m.UpdatedModal
+	}
+
+	m.UpdatedModal = &modal{
+		Blocks:    &Blocks{},
+		ChannelID: m.parent.ChannelID,
+		Title:     title,
+		HasParent: true,
+		Replaces:  true,
+	}
+	m.actionQueue.enqueue(m.UpdatedModal)
+	return m.UpdatedModal
+}
+
+func (m *modalSubmission) Close() {
+	m.closed = true
+}
+
+func (m *modalSubmission) Reject(errors map[string]string) {
+	if m.errors == nil {
+		m.errors = make(map[string]string)
+	}
+	for field, message := range errors {
+		m.errors[field] = message
+	}
+}
+
 func (m *modalSubmission) exec(ctx context.Context, req request) (interface{}, error) {
+	if len(m.errors) > 0 {
+		blockErrors := make(map[string]string)
+		for field, message := range m.errors {
+			blockID, ok := m.parent.fieldBlockID(field)
+			if !ok {
+				blockID = field
+			}
+			blockErrors[blockID] = message
+		}
+		return slack.NewErrorsViewSubmissionResponse(blockErrors), nil
+	}
+	if m.closed {
+		// An empty response closes only the current view
+		return map[string]interface{}{}, nil
+	}
+	if m.NextModal != nil || m.UpdatedModal != nil {
+		// The response will be provided by the new modal
+		return nil, nil
+	}
+
 	var payload interface{} = map[string]interface{}{}
 	payload = slack.NewClearViewSubmissionResponse()
 	return payload, nil
@@ -222,6 +284,9 @@ func (m *modalSubmission) populateEvent(ctx context.Context, p eventPopulation,
 	if m.NextModal != nil {
 		return m.NextModal.populateEvent(ctx, p, depth+1)
 	}
+	if m.UpdatedModal != nil {
+		return m.UpdatedModal.populateEvent(ctx, p, depth+1)
+	}
 
 	return nil
 }
@@ -233,6 +298,9 @@ func (*modalSubmission) Type() string {
 func (ms *modalSubmission) Data() interface{} {
 	// TODO: This should be more well defined
 	return map[string]interface{}{
-		"next_modal": ms.NextModal,
+		"next_modal":    ms.NextModal,
+		"updated_modal": ms.UpdatedModal,
+		"errors":        ms.errors,
+		"closed":        ms.closed,
 	}
 }
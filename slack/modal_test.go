@@ -0,0 +1,200 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestModalSubmissionRejected(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if slash := ev.ReceiveSlashCommand("/signup"); slash != nil {
+				modal := slash.Modal("Sign up")
+				name := modal.TextInput("Name", "", "")
+				email := modal.TextInput("Email", "", "", spanner.WithID("email"))
+				if submission := modal.SubmitButton("Submit"); submission != nil {
+					errors := make(map[string]string)
+					if name == "" {
+						errors["Name"] = "Name is required"
+					}
+					if !strings.Contains(email, "@") {
+						errors["email"] = "Must be an email address"
+					}
+					if len(errors) > 0 {
+						submission.Reject(errors)
+					}
+				}
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(slashCommandEvent(slack.SlashCommand{
+		Command:   "/signup",
+		ChannelID: "ABC123",
+	}))
+
+	if len(client.viewsOpened) != 1 {
+		t.Fatalf("expected one view to be opened, got %d", len(client.viewsOpened))
+	}
+	view := client.viewsOpened[0]
+	client.acked = nil
+
+	client.SendEventToApp(viewSubmissionEvent(view, map[string]map[string]slack.BlockAction{
+		"input-0": {"input0action": {Value: ""}},
+		"email":   {"emailaction": {Value: "not-an-email"}},
+	}))
+
+	if len(client.acked) != 1 {
+		t.Fatalf("expected one acknowledgement, got %d", len(client.acked))
+	}
+	response, ok := client.acked[0].(*slack.ViewSubmissionResponse)
+	if !ok {
+		t.Fatalf("expected view submission response, got %T", client.acked[0])
+	}
+	if response.ResponseAction != slack.RAErrors {
+		t.Errorf("expected errors response, got %q", response.ResponseAction)
+	}
+	if got := response.Errors["input-0"]; got != "Name is required" {
+		t.Errorf("expected error for name, got %q", got)
+	}
+	if got := response.Errors["email"]; got != "Must be an email address" {
+		t.Errorf("expected error for email, got %q", got)
+	}
+
+	client.acked = nil
+	client.SendEventToApp(viewSubmissionEvent(view, map[string]map[string]slack.BlockAction{
+		"input-0": {"input0action": {Value: "Tom"}},
+		"email":   {"emailaction": {Value: "tom@example.com"}},
+	}))
+
+	response, ok = client.acked[0].(*slack.ViewSubmissionResponse)
+	if !ok {
+		t.Fatalf("expected view submission response, got %T", client.acked[0])
+	}
+	if response.ResponseAction != slack.RAClear {
+		t.Errorf("expected clear response, got %q", response.ResponseAction)
+	}
+}
+
+func TestModalSubmissionUpdate(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var refreshed bool
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if slash := ev.ReceiveSlashCommand("/deploy"); slash != nil {
+				modal := slash.Modal("Deploy")
+				modal.TextInput("Service", "", "")
+				if submission := modal.SubmitButton("Deploy"); submission != nil {
+					processing := submission.UpdateModal("Deploying")
+					processing.PlainText("Deployment in progress")
+					if processing.Button("Refresh") {
+						refreshed = true
+					}
+				}
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(slashCommandEvent(slack.SlashCommand{
+		Command:   "/deploy",
+		ChannelID: "ABC123",
+	}))
+	if len(client.viewsOpened) != 1 {
+		t.Fatalf("expected one view to be opened, got %d", len(client.viewsOpened))
+	}
+	client.acked = nil
+
+	client.SendEventToApp(viewSubmissionEvent(client.viewsOpened[0], map[string]map[string]slack.BlockAction{
+		"input-0": {"input0action": {Value: "api"}},
+	}))
+
+	if len(client.acked) != 1 {
+		t.Fatalf("expected one acknowledgement, got %d", len(client.acked))
+	}
+	response, ok := client.acked[0].(*slack.ViewSubmissionResponse)
+	if !ok {
+		t.Fatalf("expected view submission response, got %T", client.acked[0])
+	}
+	if response.ResponseAction != slack.RAUpdate {
+		t.Fatalf("expected update response, got %q", response.ResponseAction)
+	}
+	if response.View.Title.Text != "Deploying" {
+		t.Errorf("expected updated view title, got %q", response.View.Title.Text)
+	}
+
+	// Interacting with the updated view should update it in place
+	client.SendEventToApp(viewInteractionEvent(
+		slack.View{
+			ID:              "V123",
+			PrivateMetadata: response.View.PrivateMetadata,
+		},
+		slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{
+				{
+					Type:    "button",
+					BlockID: "input-0",
+					Text:    slack.TextBlockObject{Text: "Refresh"},
+				},
+			},
+		},
+	))
+
+	if !refreshed {
+		t.Errorf("expected button on updated view to be clicked")
+	}
+	if len(client.viewsUpdated) != 1 {
+		t.Fatalf("expected one view to be updated, got %d", len(client.viewsUpdated))
+	}
+	if got := client.viewsUpdated[0]; got.viewID != "V123" || got.view.Title.Text != "Deploying" {
+		t.Errorf("unexpected view update: %v %q", got.viewID, got.view.Title.Text)
+	}
+}
+
+func TestModalSubmissionClose(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if slash := ev.ReceiveSlashCommand("/close"); slash != nil {
+				modal := slash.Modal("Close me")
+				if submission := modal.SubmitButton("Close"); submission != nil {
+					submission.Close()
+				}
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(slashCommandEvent(slack.SlashCommand{
+		Command:   "/close",
+		ChannelID: "ABC123",
+	}))
+	client.acked = nil
+
+	client.SendEventToApp(viewSubmissionEvent(client.viewsOpened[0], nil))
+
+	if len(client.acked) != 1 {
+		t.Fatalf("expected one acknowledgement, got %d", len(client.acked))
+	}
+	if response, ok := client.acked[0].(map[string]interface{}); !ok || len(response) != 0 {
+		t.Errorf("expected an empty response, got %#v", client.acked[0])
+	}
+}
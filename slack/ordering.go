@@ -0,0 +1,93 @@
+package slack
+
+import (
+	"hash/fnv"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Ordering determines which events must be processed in the order they were received
+// when events are processed concurrently.
+type Ordering int
+
+const (
+	// OrderByChannel processes events from the same channel in order.
+	OrderByChannel Ordering = iota
+	// OrderByUser processes events from the same user in order.
+	OrderByUser
+	// OrderByMessage processes interactions with the same message or modal view in order.
+	OrderByMessage
+)
+
+// orderingKey returns a key identifying the events that must be processed in order with this event.
+// Events that do not belong to a channel, user or message return an empty key.
+func orderingKey(ce combinedEvent, ordering Ordering) string {
+	if ce.ev == nil {
+		return ""
+	}
+
+	var channelID, userID, messageID string
+
+	switch data := ce.ev.Data.(type) {
+	case slack.SlashCommand:
+		channelID = data.ChannelID
+		userID = data.UserID
+		messageID = data.TriggerID
+	case slackevents.EventsAPIEvent:
+		switch ev := data.InnerEvent.Data.(type) {
+		case *slackevents.MessageEvent:
+			channelID = ev.Channel
+			userID = ev.User
+			messageID = ev.Channel + "/" + ev.TimeStamp
+		case *slackevents.AppMentionEvent:
+			channelID = ev.Channel
+			userID = ev.User
+			messageID = ev.Channel + "/" + ev.TimeStamp
+		case *slackevents.ReactionAddedEvent:
+			channelID = ev.Item.Channel
+			userID = ev.User
+			messageID = ev.Item.Channel + "/" + ev.Item.Timestamp
+		case *slackevents.ReactionRemovedEvent:
+			channelID = ev.Item.Channel
+			userID = ev.User
+			messageID = ev.Item.Channel + "/" + ev.Item.Timestamp
+		}
+	case slack.InteractionCallback:
+		channelID = data.Channel.ID
+		userID = data.User.ID
+		switch {
+		case data.View.RootViewID != "":
+			messageID = data.View.RootViewID
+		case data.View.ID != "":
+			messageID = data.View.ID
+		case data.Container.MessageTs != "":
+			messageID = data.Container.ChannelID + "/" + data.Container.MessageTs
+		default:
+			messageID = data.Channel.ID + "/" + data.Message.Timestamp
+		}
+	}
+
+	switch ordering {
+	case OrderByUser:
+		return userID
+	case OrderByMessage:
+		return messageID
+	default:
+		return channelID
+	}
+}
+
+// workerIndex selects the worker for an event so that events with the same key
+// are always handled by the same worker.
+// Events without a key are distributed across all workers.
+func workerIndex(key string, workers int, next *int) int {
+	if key == "" {
+		*next = (*next + 1) % workers
+		return *next
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(workers))
+}
@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/theothertomelliott/spanner"
+)
+
+var _ spanner.Reaction = &reaction{}
+
+type reaction struct {
+	eventMetadata
+
+	EmojiInternal  string `json:"emoji"`
+	ItemTSInternal string `json:"item_ts"`
+}
+
+func newReaction(client socketClient, userID string, emoji string, item slackevents.Item) *reaction {
+	return &reaction{
+		eventMetadata: eventMetadata{
+			ChannelInfo: &channel{
+				client:     client,
+				IDInternal: item.Channel,
+			},
+			UserInfo: &user{
+				client:     client,
+				IDInternal: userID,
+			},
+		},
+		EmojiInternal:  emoji,
+		ItemTSInternal: item.Timestamp,
+	}
+}
+
+func (r *reaction) Emoji() string {
+	return r.EmojiInternal
+}
+
+func (r *reaction) ItemTS() string {
+	return r.ItemTSInternal
+}
+
+var _ action = &reactionAction{}
+
+type reactionAction struct {
+	emoji     string
+	channelID string
+	timestamp string
+	remove    bool
+
+	errFunc spanner.ErrorFunc
+}
+
+func (r *reactionAction) ErrorFunc(ef spanner.ErrorFunc) {
+	r.errFunc = ef
+}
+
+func (r *reactionAction) getErrorFunc() spanner.ErrorFunc {
+	return r.errFunc
+}
+
+// Data implements action.
+func (r *reactionAction) Data() interface{} {
+	// TODO: This should be more well-defined
+	return map[string]interface{}{
+		"emoji":      r.emoji,
+		"channel_id": r.channelID,
+		"timestamp":  r.timestamp,
+	}
+}
+
+// Type implements action.
+func (r *reactionAction) Type() string {
+	if r.remove {
+		return "remove_reaction"
+	}
+	return "add_reaction"
+}
+
+// exec implements action.
+func (r *reactionAction) exec(ctx context.Context, req request) (interface{}, error) {
+	item := slack.NewRefToMessage(req.channelID(r.channelID), r.timestamp)
+	if r.remove {
+		if err := req.client.RemoveReactionContext(ctx, r.emoji, item); err != nil {
+			return nil, fmt.Errorf("removing reaction: %w", renderSlackError(err))
+		}
+		return nil, nil
+	}
+
+	if err := req.client.AddReactionContext(ctx, r.emoji, item); err != nil {
+		return nil, fmt.Errorf("adding reaction: %w", renderSlackError(err))
+	}
+	return nil, nil
+}
@@ -0,0 +1,65 @@
+package slack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestReactions(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var reactionErr error
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if r := ev.ReceiveReactionAdded(); r != nil && r.Emoji() == "white_check_mark" {
+				ev.AddReaction("tada", r.Channel().ID(), r.ItemTS())
+				ev.RemoveReaction("eyes", "invalid_channel", r.ItemTS()).ErrorFunc(func(ctx context.Context, ev spanner.ErrorEvent) {
+					reactionErr = ev.ReceiveError()
+				})
+			}
+			if r := ev.ReceiveReactionRemoved(); r != nil && r.Emoji() == "white_check_mark" {
+				ev.RemoveReaction("tada", r.Channel().ID(), r.ItemTS())
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	item := slackevents.Item{
+		Type:      "message",
+		Channel:   "ABC123",
+		Timestamp: "1700000000.000100",
+	}
+
+	client.SendEventToApp(reactionAddedEvent(slackevents.ReactionAddedEvent{
+		User:     "DEF456",
+		Reaction: "white_check_mark",
+		Item:     item,
+	}))
+
+	if len(client.reactions) != 1 {
+		t.Fatalf("expected one reaction to be added, got %d", len(client.reactions))
+	}
+	if r := client.reactions[0]; r.emoji != "tada" || r.item.Channel != "ABC123" || r.item.Timestamp != item.Timestamp || r.removed {
+		t.Errorf("unexpected reaction: %+v", r)
+	}
+	if reactionErr == nil {
+		t.Errorf("expected an error removing a reaction from an invalid channel")
+	}
+	client.reactions = nil
+
+	client.SendEventToApp(reactionRemovedEvent(slackevents.ReactionRemovedEvent{
+		User:     "DEF456",
+		Reaction: "white_check_mark",
+		Item:     item,
+	}))
+
+	if len(client.reactions) != 1 || !client.reactions[0].removed || client.reactions[0].emoji != "tada" {
+		t.Errorf("expected reaction to be removed, got %+v", client.reactions)
+	}
+}
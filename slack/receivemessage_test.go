@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 	"github.com/theothertomelliott/spanner"
 )
@@ -19,11 +20,9 @@ func TestReceiveMessageContent(t *testing.T) {
 	}
 	client.SendEventToAppAsync(messageEvent(message))
 
-	testApp.Run(func(ctx context.Context, evt spanner.Event) {
-		defer func() {
-			// Stop the client
-			close(client.stop)
-		}()
+	ctx, cancel := context.WithCancel(context.Background())
+	testApp.RunContext(ctx, func(ctx context.Context, evt spanner.Event) {
+		defer cancel()
 
 		// Expect a message with the right channel id attached
 		msg := evt.ReceiveMessage()
@@ -43,3 +42,99 @@ func TestReceiveMessageContent(t *testing.T) {
 	})
 
 }
+
+func TestReplyInThread(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var (
+		threadReply bool
+		clicked     bool
+	)
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if msg := ev.ReceiveMessage(); msg != nil {
+				threadReply = msg.IsThreadReply()
+
+				var reply spanner.Message
+				if msg.Text() == "broadcast" {
+					reply = msg.ReplyBroadcast()
+				} else {
+					reply = msg.Reply()
+				}
+				reply.PlainText("In the thread")
+				if reply.Button("Click") {
+					clicked = true
+				}
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Channel:         "ABC123",
+		User:            "DEF456",
+		Text:            "hello",
+		TimeStamp:       "1700000001.000200",
+		ThreadTimeStamp: "1700000000.000100",
+	}))
+
+	if !threadReply {
+		t.Errorf("expected message to be a thread reply")
+	}
+	if len(client.messagesSent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(client.messagesSent))
+	}
+	sent := client.messagesSent[0]
+	if sent.threadTS != "1700000000.000100" || sent.broadcast {
+		t.Errorf("expected reply in thread without broadcast, got thread %q, broadcast %v", sent.threadTS, sent.broadcast)
+	}
+	client.messagesSent = nil
+
+	// Interacting with the reply should update it in place
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		"1700000002.000300",
+		sent.metadata,
+		slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{
+				{
+					Type:    "button",
+					BlockID: "input-0",
+					Text:    slack.TextBlockObject{Text: "Click"},
+				},
+			},
+		},
+		nil,
+	))
+
+	if !clicked {
+		t.Errorf("expected button to be clicked")
+	}
+	if len(client.messagesSent) != 0 {
+		t.Errorf("expected no new messages, got %d", len(client.messagesSent))
+	}
+	if len(client.messagesUpdated) != 1 || client.messagesUpdated[0].timestamp != "1700000002.000300" {
+		t.Errorf("expected reply to be updated, got %+v", client.messagesUpdated)
+	}
+
+	// Replying to a message that isn't in a thread starts a new thread
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Channel:   "ABC123",
+		User:      "DEF456",
+		Text:      "broadcast",
+		TimeStamp: "1700000003.000400",
+	}))
+
+	if threadReply {
+		t.Errorf("expected message not to be a thread reply")
+	}
+	if len(client.messagesSent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(client.messagesSent))
+	}
+	if sent := client.messagesSent[0]; sent.threadTS != "1700000003.000400" || !sent.broadcast {
+		t.Errorf("expected broadcast reply in new thread, got thread %q, broadcast %v", sent.threadTS, sent.broadcast)
+	}
+}
@@ -22,11 +22,9 @@ func TestReceiveSlashCommand(t *testing.T) {
 		slashCommand,
 	))
 
-	testApp.Run(func(ctx context.Context, evt spanner.Event) {
-		defer func() {
-			// Stop the client
-			close(client.stop)
-		}()
+	ctx, cancel := context.WithCancel(context.Background())
+	testApp.RunContext(ctx, func(ctx context.Context, evt spanner.Event) {
+		defer cancel()
 
 		// Expect a slash command with the expected command is received
 		cmd := evt.ReceiveSlashCommand(slashCommand.Command)
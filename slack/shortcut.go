@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+var _ spanner.GlobalShortcut = &shortcut{}
+var _ spanner.MessageShortcut = &shortcut{}
+var _ eventPopulator = &shortcut{}
+
+// shortcut represents either a global or message shortcut.
+// MessageInternal is only set for message shortcuts.
+type shortcut struct {
+	actionQueue *actionQueue
+
+	eventMetadata
+
+	TriggerID       string           `json:"trigger_id"`
+	CallbackID      string           `json:"callback_id"`
+	MessageInternal *receivedMessage `json:"message,omitempty"`
+	ModalInternal   *modal           `json:"modal"`
+}
+
+func newShortcut(q *actionQueue, metadata eventMetadata, ic slack.InteractionCallback) *shortcut {
+	return &shortcut{
+		actionQueue:   q,
+		eventMetadata: metadata,
+		TriggerID:     ic.TriggerID,
+		CallbackID:    ic.CallbackID,
+	}
+}
+
+func (s *shortcut) Message() spanner.ReceivedMessage {
+	if s.MessageInternal == nil {
+		return nil
+	}
+	return s.MessageInternal
+}
+
+func (s *shortcut) Modal(title string) spanner.Modal {
+	if s == nil {
+		return nil
+	}
+	if s.ModalInternal != nil {
+		return s.ModalInternal
+	}
+	s.ModalInternal = &modal{
+		Blocks:    &Blocks{},
+		Title:     title,
+		triggerID: s.TriggerID,
+	}
+	if s.ChannelInfo != nil {
+		s.ModalInternal.ChannelID = s.ChannelInfo.IDInternal
+	}
+	s.actionQueue.enqueue(s.ModalInternal)
+	return s.ModalInternal
+}
+
+func (s *shortcut) populateEvent(ctx context.Context, p eventPopulation, depth int) error {
+	if s.ModalInternal != nil {
+		return s.ModalInternal.populateEvent(ctx, p, depth+1)
+	}
+	return nil
+}
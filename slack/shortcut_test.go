@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestGlobalShortcut(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	var submitted string
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if ev.ReceiveGlobalShortcut("other_shortcut") != nil {
+				t.Errorf("did not expect shortcut with a different callback ID")
+			}
+			if shortcut := ev.ReceiveGlobalShortcut("new_ticket"); shortcut != nil {
+				if shortcut.Channel() != nil {
+					t.Errorf("expected no channel for a global shortcut")
+				}
+				modal := shortcut.Modal("New ticket")
+				title := modal.TextInput("Title", "", "")
+				if modal.SubmitButton("Create") != nil {
+					submitted = title
+				}
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(shortcutEvent(slack.InteractionTypeShortcut, "new_ticket", slack.Message{}))
+
+	if len(client.viewsOpened) != 1 {
+		t.Fatalf("expected one view to be opened, got %d", len(client.viewsOpened))
+	}
+
+	client.SendEventToApp(viewSubmissionEvent(client.viewsOpened[0], map[string]map[string]slack.BlockAction{
+		"input-0": {"input0action": {Value: "Broken build"}},
+	}))
+
+	if submitted != "Broken build" {
+		t.Errorf("expected modal to be submitted with title, got %q", submitted)
+	}
+}
+
+func TestMessageShortcut(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if shortcut := ev.ReceiveMessageShortcut("summarize"); shortcut != nil {
+				msg := shortcut.Message()
+				if msg.Text() != "the build is broken" {
+					t.Errorf("unexpected message text: %q", msg.Text())
+				}
+				if msg.User().ID() != "GHI789" {
+					t.Errorf("expected message author GHI789, got %q", msg.User().ID())
+				}
+				if msg.Channel().ID() != "ABC123" {
+					t.Errorf("expected channel ABC123, got %q", msg.Channel().ID())
+				}
+				if shortcut.User().ID() != "DEF456" {
+					t.Errorf("expected shortcut user DEF456, got %q", shortcut.User().ID())
+				}
+				shortcut.Modal("Summary").PlainText(msg.Text())
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(shortcutEvent(slack.InteractionTypeMessageAction, "summarize", slack.Message{
+		Msg: slack.Msg{
+			User:      "GHI789",
+			Text:      "the build is broken",
+			Timestamp: "1700000000.000100",
+		},
+	}))
+
+	if len(client.viewsOpened) != 1 {
+		t.Fatalf("expected one view to be opened, got %d", len(client.viewsOpened))
+	}
+	if title := client.viewsOpened[0].Title.Text; title != "Summary" {
+		t.Errorf("unexpected modal title: %q", title)
+	}
+}
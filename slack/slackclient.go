@@ -24,7 +24,7 @@ type socketClient interface {
 	// AddPin(channel string, item ItemRef) error
 	// AddPinContext(ctx context.Context, channel string, item ItemRef) error
 	// AddReaction(name string, item ItemRef) error
-	// AddReactionContext(ctx context.Context, name string, item ItemRef) error
+	AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error
 	// AddRemoteFile(params RemoteFileParameters) (*RemoteFile, error)
 	// AddRemoteFileContext(ctx context.Context, params RemoteFileParameters) (remotefile *RemoteFile, err error)
 	// AddStar(channel string, item ItemRef) error
@@ -32,7 +32,7 @@ type socketClient interface {
 	// AddUserReminder(userID string, text string, time string) (*Reminder, error)
 	// AddUserReminderContext(ctx context.Context, userID string, text string, time string) (*Reminder, error)
 	// ArchiveConversation(channelID string) error
-	// ArchiveConversationContext(ctx context.Context, channelID string) error
+	ArchiveConversationContext(ctx context.Context, channelID string) error
 	// AuthTest() (response *AuthTestResponse, error error)
 	// AuthTestContext(ctx context.Context) (response *AuthTestResponse, err error)
 	// CloseConversation(channelID string) (noOp bool, alreadyClosed bool, err error)
@@ -40,7 +40,7 @@ type socketClient interface {
 	// ConnectRTM() (info *Info, websocketURL string, err error)
 	// ConnectRTMContext(ctx context.Context) (info *Info, websocketURL string, err error)
 	// CreateConversation(params CreateConversationParams) (*Channel, error)
-	// CreateConversationContext(ctx context.Context, params CreateConversationParams) (*Channel, error)
+	CreateConversationContext(ctx context.Context, params slack.CreateConversationParams) (*slack.Channel, error)
 	// CreateUserGroup(userGroup UserGroup) (UserGroup, error)
 	// CreateUserGroupContext(ctx context.Context, userGroup UserGroup) (UserGroup, error)
 	// Debug() bool
@@ -51,7 +51,7 @@ type socketClient interface {
 	// DeleteFileCommentContext(ctx context.Context, fileID string, commentID string) (err error)
 	// DeleteFileContext(ctx context.Context, fileID string) (err error)
 	// DeleteMessage(channel string, messageTimestamp string) (string, string, error)
-	// DeleteMessageContext(ctx context.Context, channel string, messageTimestamp string) (string, string, error)
+	DeleteMessageContext(ctx context.Context, channel string, messageTimestamp string) (string, string, error)
 	// DeleteReminder(id string) error
 	// DeleteReminderContext(ctx context.Context, id string) error
 	// DeleteScheduledMessage(params *DeleteScheduledMessageParameters) (bool, error)
@@ -87,7 +87,7 @@ type socketClient interface {
 	// GetConversationReplies(params *GetConversationRepliesParameters) (msgs []Message, hasMore bool, nextCursor string, err error)
 	// GetConversationRepliesContext(ctx context.Context, params *GetConversationRepliesParameters) (msgs []Message, hasMore bool, nextCursor string, err error)
 	// GetConversations(params *GetConversationsParameters) (channels []Channel, nextCursor string, err error)
-	// GetConversationsContext(ctx context.Context, params *GetConversationsParameters) (channels []Channel, nextCursor string, err error)
+	GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) (channels []slack.Channel, nextCursor string, err error)
 	// GetConversationsForUser(params *GetConversationsForUserParameters) (channels []Channel, nextCursor string, err error)
 	// GetConversationsForUserContext(ctx context.Context, params *GetConversationsForUserParameters) (channels []Channel, nextCursor string, err error)
 	// GetDNDInfo(user *string) (*DNDStatus, error)
@@ -131,13 +131,13 @@ type socketClient interface {
 	// GetUserPrefs() (*UserPrefsCarrier, error)
 	// GetUserPrefsContext(ctx context.Context) (*UserPrefsCarrier, error)
 	// GetUserPresence(user string) (*UserPresence, error)
-	// GetUserPresenceContext(ctx context.Context, user string) (*UserPresence, error)
+	GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error)
 	// GetUserProfile(params *GetUserProfileParameters) (*UserProfile, error)
-	// GetUserProfileContext(ctx context.Context, params *GetUserProfileParameters) (*UserProfile, error)
+	GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error)
 	// GetUsers(options ...GetUsersOption) ([]User, error)
-	// GetUsersContext(ctx context.Context, options ...GetUsersOption) (results []User, err error)
+	GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) (results []slack.User, err error)
 	// GetUsersInConversation(params *GetUsersInConversationParameters) ([]string, string, error)
-	// GetUsersInConversationContext(ctx context.Context, params *GetUsersInConversationParameters) ([]string, string, error)
+	GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error)
 	// GetUsersInfo(users ...string) (*[]User, error)
 	// GetUsersInfoContext(ctx context.Context, users ...string) (*[]User, error)
 	// GetUsersPaginated(options ...GetUsersOption) UserPagination
@@ -148,13 +148,13 @@ type socketClient interface {
 	// InviteToTeam(teamName string, firstName string, lastName string, emailAddress string) error
 	// InviteToTeamContext(ctx context.Context, teamName string, firstName string, lastName string, emailAddress string) error
 	// InviteUsersToConversation(channelID string, users ...string) (*Channel, error)
-	// InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*Channel, error)
+	InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error)
 	//JoinConversation(channelID string) (*slack.Channel, string, []string, error)
 	JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error)
 	// KickUserFromConversation(channelID string, user string) error
-	// KickUserFromConversationContext(ctx context.Context, channelID string, user string) error
+	KickUserFromConversationContext(ctx context.Context, channelID string, user string) error
 	// LeaveConversation(channelID string) (bool, error)
-	// LeaveConversationContext(ctx context.Context, channelID string) (bool, error)
+	LeaveConversationContext(ctx context.Context, channelID string) (bool, error)
 	// ListAllStars() ([]Item, error)
 	// ListAllStarsContext(ctx context.Context) (results []Item, err error)
 	// ListBookmarks(channelID string) ([]Bookmark, error)
@@ -181,17 +181,17 @@ type socketClient interface {
 	// MuteChat(channelID string) (*UserPrefsCarrier, error)
 	// NewRTM(options ...RTMOption) *RTM
 	// OpenConversation(params *OpenConversationParameters) (*Channel, bool, bool, error)
-	// OpenConversationContext(ctx context.Context, params *OpenConversationParameters) (*Channel, bool, bool, error)
+	OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
 	// OpenDialog(triggerID string, dialog Dialog) (err error)
 	// OpenDialogContext(ctx context.Context, triggerID string, dialog Dialog) (err error)
 	// OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
 	OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
 	// PostEphemeral(channelID string, userID string, options ...MsgOption) (string, error)
-	// PostEphemeralContext(ctx context.Context, channelID string, userID string, options ...MsgOption) (timestamp string, err error)
+	PostEphemeralContext(ctx context.Context, channelID string, userID string, options ...slack.MsgOption) (timestamp string, err error)
 	// PostMessage(channelID string, options ...MsgOption) (string, string, error)
 	// PostMessageContext(ctx context.Context, channelID string, options ...MsgOption) (string, string, error)
 	// PublishView(userID string, view HomeTabViewRequest, hash string) (*ViewResponse, error)
-	// PublishViewContext(ctx context.Context, userID string, view HomeTabViewRequest, hash string) (*ViewResponse, error)
+	PublishViewContext(ctx context.Context, userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error)
 	// PushView(triggerID string, view ModalViewRequest) (*ViewResponse, error)
 	// PushViewContext(ctx context.Context, triggerID string, view ModalViewRequest) (*ViewResponse, error)
 	// RemoveBookmark(channelID string, bookmarkID string) error
@@ -199,7 +199,7 @@ type socketClient interface {
 	// RemovePin(channel string, item ItemRef) error
 	// RemovePinContext(ctx context.Context, channel string, item ItemRef) error
 	// RemoveReaction(name string, item ItemRef) error
-	// RemoveReactionContext(ctx context.Context, name string, item ItemRef) error
+	RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error
 	// RemoveRemoteFile(externalID string, fileID string) (err error)
 	// RemoveRemoteFileContext(ctx context.Context, externalID string, fileID string) (err error)
 	// RemoveStar(channel string, item ItemRef) error
@@ -221,14 +221,14 @@ type socketClient interface {
 	// SendAuthRevoke(token string) (*AuthRevokeResponse, error)
 	// SendAuthRevokeContext(ctx context.Context, token string) (*AuthRevokeResponse, error)
 
-	SendMessageWithMetadata(ctx context.Context, channel string, blocks []slack.Block, metadata slack.SlackMetadata) (string, string, string, error)
+	SendMessageWithMetadata(ctx context.Context, channel string, blocks []slack.Block, metadata slack.SlackMetadata, options ...slack.MsgOption) (string, string, string, error)
 	// SendMessage(channel string, options ...slack.MsgOption) (string, string, string, error)
-	// SendMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (_channel string, _timestamp string, _text string, err error)
+	SendMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (_channel string, _timestamp string, _text string, err error)
 
 	// SendSSOBindingEmail(teamName string, user string) error
 	// SendSSOBindingEmailContext(ctx context.Context, teamName string, user string) error
 	// SetPurposeOfConversation(channelID string, purpose string) (*Channel, error)
-	// SetPurposeOfConversationContext(ctx context.Context, channelID string, purpose string) (*Channel, error)
+	SetPurposeOfConversationContext(ctx context.Context, channelID string, purpose string) (*slack.Channel, error)
 	// SetRegular(teamName string, user string) error
 	// SetRegularContext(ctx context.Context, teamName string, user string) error
 	// SetRestricted(teamName string, uid string, channelIds ...string) error
@@ -236,7 +236,7 @@ type socketClient interface {
 	// SetSnooze(minutes int) (*DNDStatus, error)
 	// SetSnoozeContext(ctx context.Context, minutes int) (*DNDStatus, error)
 	// SetTopicOfConversation(channelID string, topic string) (*Channel, error)
-	// SetTopicOfConversationContext(ctx context.Context, channelID string, topic string) (*Channel, error)
+	SetTopicOfConversationContext(ctx context.Context, channelID string, topic string) (*slack.Channel, error)
 	// SetUltraRestricted(teamName string, uid string, channel string) error
 	// SetUltraRestrictedContext(ctx context.Context, teamName string, uid string, channel string) error
 	// SetUserAsActive() error
@@ -289,7 +289,7 @@ type socketClient interface {
 	// UploadFile(params FileUploadParameters) (file *File, err error)
 	// UploadFileContext(ctx context.Context, params FileUploadParameters) (file *File, err error)
 	// UploadFileV2(params UploadFileV2Parameters) (*FileSummary, error)
-	// UploadFileV2Context(ctx context.Context, params UploadFileV2Parameters) (file *FileSummary, err error)
+	UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (file *slack.FileSummary, err error)
 	// WorkflowStepCompleted(workflowStepExecuteID string, options ...WorkflowStepCompletedRequestOption) error
 	// WorkflowStepFailed(workflowStepExecuteID string, errorMessage string) error
 }
@@ -307,33 +307,128 @@ func (nilSocketClient) Ack(req socketmode.Request, payload ...interface{}) {
 	panic("unimplemented")
 }
 
+// AddReactionContext implements socketClient.
+func (nilSocketClient) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	panic("unimplemented")
+}
+
+// ArchiveConversationContext implements socketClient.
+func (nilSocketClient) ArchiveConversationContext(ctx context.Context, channelID string) error {
+	panic("unimplemented")
+}
+
+// CreateConversationContext implements socketClient.
+func (nilSocketClient) CreateConversationContext(ctx context.Context, params slack.CreateConversationParams) (*slack.Channel, error) {
+	panic("unimplemented")
+}
+
+// DeleteMessageContext implements socketClient.
+func (nilSocketClient) DeleteMessageContext(ctx context.Context, channel string, messageTimestamp string) (string, string, error) {
+	panic("unimplemented")
+}
+
 // GetConversationInfoContext implements socketClient.
 func (nilSocketClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
 	panic("unimplemented")
 }
 
+// GetConversationsContext implements socketClient.
+func (nilSocketClient) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) (channels []slack.Channel, nextCursor string, err error) {
+	panic("unimplemented")
+}
+
 // GetUserInfoContext implements socketClient.
 func (nilSocketClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
 	panic("unimplemented")
 }
 
+// GetUserPresenceContext implements socketClient.
+func (nilSocketClient) GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error) {
+	panic("unimplemented")
+}
+
+// GetUserProfileContext implements socketClient.
+func (nilSocketClient) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
+	panic("unimplemented")
+}
+
+// GetUsersContext implements socketClient.
+func (nilSocketClient) GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) (results []slack.User, err error) {
+	panic("unimplemented")
+}
+
+// GetUsersInConversationContext implements socketClient.
+func (nilSocketClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+	panic("unimplemented")
+}
+
+// InviteUsersToConversationContext implements socketClient.
+func (nilSocketClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
+	panic("unimplemented")
+}
+
 // JoinConversationContext implements socketClient.
 func (nilSocketClient) JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error) {
 	panic("unimplemented")
 }
 
+// KickUserFromConversationContext implements socketClient.
+func (nilSocketClient) KickUserFromConversationContext(ctx context.Context, channelID string, user string) error {
+	panic("unimplemented")
+}
+
+// LeaveConversationContext implements socketClient.
+func (nilSocketClient) LeaveConversationContext(ctx context.Context, channelID string) (bool, error) {
+	panic("unimplemented")
+}
+
+// OpenConversationContext implements socketClient.
+func (nilSocketClient) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	panic("unimplemented")
+}
+
 // OpenViewContext implements socketClient.
 func (nilSocketClient) OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
 	panic("unimplemented")
 }
 
+// PostEphemeralContext implements socketClient.
+func (nilSocketClient) PostEphemeralContext(ctx context.Context, channelID string, userID string, options ...slack.MsgOption) (string, error) {
+	panic("unimplemented")
+}
+
+// PublishViewContext implements socketClient.
+func (nilSocketClient) PublishViewContext(ctx context.Context, userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error) {
+	panic("unimplemented")
+}
+
+// RemoveReactionContext implements socketClient.
+func (nilSocketClient) RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	panic("unimplemented")
+}
+
 // RunContext implements socketClient.
 func (nilSocketClient) RunContext(ctx context.Context) error {
 	panic("unimplemented")
 }
 
+// SendMessageContext implements socketClient.
+func (nilSocketClient) SendMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, string, error) {
+	panic("unimplemented")
+}
+
 // SendMessageWithMetadata implements socketClient.
-func (nilSocketClient) SendMessageWithMetadata(ctx context.Context, channel string, blocks []slack.Block, metadata slack.SlackMetadata) (string, string, string, error) {
+func (nilSocketClient) SendMessageWithMetadata(ctx context.Context, channel string, blocks []slack.Block, metadata slack.SlackMetadata, options ...slack.MsgOption) (string, string, string, error) {
+	panic("unimplemented")
+}
+
+// SetPurposeOfConversationContext implements socketClient.
+func (nilSocketClient) SetPurposeOfConversationContext(ctx context.Context, channelID string, purpose string) (*slack.Channel, error) {
+	panic("unimplemented")
+}
+
+// SetTopicOfConversationContext implements socketClient.
+func (nilSocketClient) SetTopicOfConversationContext(ctx context.Context, channelID string, topic string) (*slack.Channel, error) {
 	panic("unimplemented")
 }
 
@@ -346,3 +441,8 @@ func (nilSocketClient) UpdateMessageWithMetadata(ctx context.Context, channel st
 func (nilSocketClient) UpdateViewContext(ctx context.Context, view slack.ModalViewRequest, externalID string, hash string, viewID string) (*slack.ViewResponse, error) {
 	panic("unimplemented")
 }
+
+// UploadFileV2Context implements socketClient.
+func (nilSocketClient) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	panic("unimplemented")
+}
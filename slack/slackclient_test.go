@@ -3,6 +3,9 @@ package slack
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/url"
+	"strings"
 	"sync"
 
 	"github.com/slack-go/slack"
@@ -19,7 +22,6 @@ func newTestClient(validChannels []string) *testClient {
 
 	return &testClient{
 		Events:        make(chan socketmode.Event),
-		stop:          make(chan struct{}),
 		postEvent:     make(chan interface{}, 10),
 		validChannels: validChannelMap,
 	}
@@ -30,23 +32,86 @@ type testClient struct {
 
 	messagesSent    []sentMessage
 	messagesUpdated []updatedMessage
+	messagesDeleted []deletedMessage
+	ephemeralSent   []sentEphemeral
+	responses       []sentResponse
+	filesUploaded   []uploadedFile
+	viewsOpened     []slack.ModalViewRequest
+	viewsUpdated    []updatedView
+	viewsPublished  []publishedView
+	reactions       []reactionChange
+	channelChanges  []channelChange
 
 	validChannels map[string]struct{}
+	users         map[string]*slack.User
+
+	userInfoRequests    int
+	channelInfoRequests int
 
 	Events chan socketmode.Event
 
 	postEvent chan interface{}
 
-	stop chan struct{}
-
 	runMtx   sync.Mutex
 	runCount int
+
+	ackMtx sync.Mutex
+	acked  []interface{}
 }
 
 type sentMessage struct {
 	channelID string
 	blocks    []slack.Block
 	metadata  slack.SlackMetadata
+	threadTS  string
+	broadcast bool
+}
+
+type updatedView struct {
+	view   slack.ModalViewRequest
+	viewID string
+}
+
+type publishedView struct {
+	userID string
+	view   slack.HomeTabViewRequest
+	hash   string
+}
+
+type reactionChange struct {
+	emoji   string
+	item    slack.ItemRef
+	removed bool
+}
+
+type sentEphemeral struct {
+	channelID string
+	userID    string
+	values    url.Values
+}
+
+type sentResponse struct {
+	responseURL string
+	values      url.Values
+}
+
+type uploadedFile struct {
+	channelID string
+	threadTS  string
+	name      string
+	content   string
+}
+
+type channelChange struct {
+	action    string
+	channelID string
+	value     string
+	userID    string
+}
+
+type deletedMessage struct {
+	channelID string
+	timestamp string
 }
 
 type updatedMessage struct {
@@ -88,26 +153,41 @@ func (r *testClient) SendEventToAppAsync(e socketmode.Event) {
 	}()
 }
 
-func (r *testClient) RunContext(context.Context) error {
+func (r *testClient) RunContext(ctx context.Context) error {
 	r.runMtx.Lock()
 	r.runCount++
 	r.runMtx.Unlock()
-	<-r.stop // Must block
+	<-ctx.Done() // Must block
 	return nil
 }
 
-func (*testClient) Ack(req socketmode.Request, payload ...interface{}) {}
+func (r *testClient) Ack(req socketmode.Request, payload ...interface{}) {
+	r.ackMtx.Lock()
+	defer r.ackMtx.Unlock()
 
-func (c *testClient) SendMessageWithMetadata(ctx context.Context, channelID string, blocks []slack.Block, metadata slack.SlackMetadata) (string, string, string, error) {
+	var p interface{}
+	if len(payload) > 0 {
+		p = payload[0]
+	}
+	r.acked = append(r.acked, p)
+}
+
+func (c *testClient) SendMessageWithMetadata(ctx context.Context, channelID string, blocks []slack.Block, metadata slack.SlackMetadata, options ...slack.MsgOption) (string, string, string, error) {
 	if _, ok := c.validChannels[channelID]; !ok {
 		return "", "", "", fmt.Errorf("invalid channel: %s", channelID)
 	}
+	_, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return "", "", "", err
+	}
 	c.messagesSent = append(c.messagesSent, sentMessage{
 		channelID: channelID,
 		blocks:    blocks,
 		metadata:  metadata,
+		threadTS:  values.Get("thread_ts"),
+		broadcast: values.Get("reply_broadcast") == "true",
 	})
-	return "", "", "", nil
+	return channelID, fmt.Sprintf("1700000000.%06d", len(c.messagesSent)), "", nil
 }
 
 func (c *testClient) UpdateMessageWithMetadata(ctx context.Context, channelID string, timestamp string, blocks []slack.Block, metadata slack.SlackMetadata) (string, string, string, error) {
@@ -122,6 +202,259 @@ func (c *testClient) UpdateMessageWithMetadata(ctx context.Context, channelID st
 	return "", timestamp, "", nil
 }
 
+func (c *testClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
+	c.userInfoRequests++
+	if u, ok := c.users[user]; ok {
+		return u, nil
+	}
+	return nil, slack.SlackErrorResponse{Err: "user_not_found"}
+}
+
+func (c *testClient) GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error) {
+	if _, ok := c.users[user]; !ok {
+		return nil, slack.SlackErrorResponse{Err: "user_not_found"}
+	}
+	return &slack.UserPresence{Presence: "active"}, nil
+}
+
+func (c *testClient) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
+	if u, ok := c.users[params.UserID]; ok {
+		return &u.Profile, nil
+	}
+	return nil, slack.SlackErrorResponse{Err: "user_not_found"}
+}
+
+func (c *testClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	c.channelInfoRequests++
+	if _, ok := c.validChannels[input.ChannelID]; !ok {
+		return nil, slack.SlackErrorResponse{Err: "channel_not_found"}
+	}
+	ch := &slack.Channel{}
+	ch.ID = input.ChannelID
+	ch.Name = "channel-" + input.ChannelID
+	ch.IsPrivate = true
+	ch.Topic.Value = "topic-" + input.ChannelID
+	return ch, nil
+}
+
+// GetUsersInConversationContext returns the members of valid channels, one user per page.
+func (c *testClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+	if _, ok := c.validChannels[params.ChannelID]; !ok {
+		return nil, "", slack.SlackErrorResponse{Err: "channel_not_found"}
+	}
+	if params.Cursor == "" {
+		return []string{"DEF456"}, "next", nil
+	}
+	return []string{"GHI789"}, "", nil
+}
+
+func (c *testClient) changeChannel(action string, channelID string, value string, userID string) error {
+	if _, ok := c.validChannels[channelID]; !ok {
+		return fmt.Errorf("invalid channel: %s", channelID)
+	}
+	c.channelChanges = append(c.channelChanges, channelChange{
+		action:    action,
+		channelID: channelID,
+		value:     value,
+		userID:    userID,
+	})
+	return nil
+}
+
+func (c *testClient) CreateConversationContext(ctx context.Context, params slack.CreateConversationParams) (*slack.Channel, error) {
+	if params.ChannelName == "" {
+		return nil, slack.SlackErrorResponse{Err: "invalid_name_required"}
+	}
+	id := fmt.Sprintf("NEW%d", len(c.validChannels))
+	c.validChannels[id] = struct{}{}
+
+	ch := &slack.Channel{}
+	ch.ID = id
+	ch.Name = params.ChannelName
+	ch.IsPrivate = params.IsPrivate
+	return ch, nil
+}
+
+func (c *testClient) SetTopicOfConversationContext(ctx context.Context, channelID string, topic string) (*slack.Channel, error) {
+	return nil, c.changeChannel("topic", channelID, topic, "")
+}
+
+func (c *testClient) SetPurposeOfConversationContext(ctx context.Context, channelID string, purpose string) (*slack.Channel, error) {
+	return nil, c.changeChannel("purpose", channelID, purpose, "")
+}
+
+func (c *testClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
+	return nil, c.changeChannel("invite", channelID, "", strings.Join(users, ","))
+}
+
+func (c *testClient) KickUserFromConversationContext(ctx context.Context, channelID string, user string) error {
+	return c.changeChannel("kick", channelID, "", user)
+}
+
+func (c *testClient) ArchiveConversationContext(ctx context.Context, channelID string) error {
+	return c.changeChannel("archive", channelID, "", "")
+}
+
+func (c *testClient) LeaveConversationContext(ctx context.Context, channelID string) (bool, error) {
+	return false, c.changeChannel("leave", channelID, "", "")
+}
+
+func (c *testClient) GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error) {
+	var users []slack.User
+	for _, u := range c.users {
+		users = append(users, *u)
+	}
+	return users, nil
+}
+
+func (c *testClient) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	var channels []slack.Channel
+	for id := range c.validChannels {
+		ch := slack.Channel{}
+		ch.ID = id
+		ch.Name = "channel-" + id
+		channels = append(channels, ch)
+	}
+	return channels, "", nil
+}
+
+func (c *testClient) OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+	c.viewsOpened = append(c.viewsOpened, view)
+	return &slack.ViewResponse{}, nil
+}
+
+func (c *testClient) UpdateViewContext(ctx context.Context, view slack.ModalViewRequest, externalID string, hash string, viewID string) (*slack.ViewResponse, error) {
+	c.viewsUpdated = append(c.viewsUpdated, updatedView{
+		view:   view,
+		viewID: viewID,
+	})
+	return &slack.ViewResponse{}, nil
+}
+
+func (c *testClient) PublishViewContext(ctx context.Context, userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error) {
+	c.viewsPublished = append(c.viewsPublished, publishedView{
+		userID: userID,
+		view:   view,
+		hash:   hash,
+	})
+	return &slack.ViewResponse{}, nil
+}
+
+func (c *testClient) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	if _, ok := c.validChannels[item.Channel]; !ok {
+		return fmt.Errorf("invalid channel: %s", item.Channel)
+	}
+	c.reactions = append(c.reactions, reactionChange{
+		emoji: name,
+		item:  item,
+	})
+	return nil
+}
+
+func (c *testClient) RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	if _, ok := c.validChannels[item.Channel]; !ok {
+		return fmt.Errorf("invalid channel: %s", item.Channel)
+	}
+	c.reactions = append(c.reactions, reactionChange{
+		emoji:   name,
+		item:    item,
+		removed: true,
+	})
+	return nil
+}
+
+func reactionAddedEvent(reactionEvent slackevents.ReactionAddedEvent) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeEventsAPI,
+		Data: slackevents.EventsAPIEvent{
+			Type: slackevents.CallbackEvent,
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Data: &reactionEvent,
+			},
+		},
+	}
+}
+
+func reactionRemovedEvent(reactionEvent slackevents.ReactionRemovedEvent) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeEventsAPI,
+		Data: slackevents.EventsAPIEvent{
+			Type: slackevents.CallbackEvent,
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Data: &reactionEvent,
+			},
+		},
+	}
+}
+
+// OpenConversationContext opens a direct message channel with an ID of "D" followed by the user ID.
+func (c *testClient) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	if len(params.Users) != 1 {
+		return nil, false, false, fmt.Errorf("expected a single user, got %v", params.Users)
+	}
+	return &slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Conversation: slack.Conversation{
+				ID:   "D" + params.Users[0],
+				IsIM: true,
+			},
+		},
+	}, false, false, nil
+}
+
+func (c *testClient) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	if _, ok := c.validChannels[params.Channel]; !ok {
+		return nil, fmt.Errorf("invalid channel: %s", params.Channel)
+	}
+	content, err := io.ReadAll(params.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) != params.FileSize {
+		return nil, fmt.Errorf("expected file size %d, got %d", params.FileSize, len(content))
+	}
+	c.filesUploaded = append(c.filesUploaded, uploadedFile{
+		channelID: params.Channel,
+		threadTS:  params.ThreadTimestamp,
+		name:      params.Filename,
+		content:   string(content),
+	})
+	return &slack.FileSummary{ID: fmt.Sprintf("F%d", len(c.filesUploaded))}, nil
+}
+
+func (c *testClient) DeleteMessageContext(ctx context.Context, channelID string, timestamp string) (string, string, error) {
+	c.messagesDeleted = append(c.messagesDeleted, deletedMessage{
+		channelID: channelID,
+		timestamp: timestamp,
+	})
+	return channelID, timestamp, nil
+}
+
+func (c *testClient) PostEphemeralContext(ctx context.Context, channelID string, userID string, options ...slack.MsgOption) (string, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return "", err
+	}
+	c.ephemeralSent = append(c.ephemeralSent, sentEphemeral{
+		channelID: channelID,
+		userID:    userID,
+		values:    values,
+	})
+	return "", nil
+}
+
+func (c *testClient) SendMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, string, error) {
+	endpoint, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return "", "", "", err
+	}
+	c.responses = append(c.responses, sentResponse{
+		responseURL: endpoint,
+		values:      values,
+	})
+	return channelID, "", "", nil
+}
+
 func messageEvent(messageEvent slackevents.MessageEvent) socketmode.Event {
 	return socketmode.Event{
 		Type: socketmode.EventTypeEventsAPI,
@@ -134,6 +467,84 @@ func messageEvent(messageEvent slackevents.MessageEvent) socketmode.Event {
 	}
 }
 
+func userChangeEvent(user slack.User) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeEventsAPI,
+		Data: slackevents.EventsAPIEvent{
+			Type: slackevents.CallbackEvent,
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Type: "user_change",
+				Data: &slack.UserChangeEvent{User: user},
+			},
+		},
+	}
+}
+
+func channelRenameEvent(channelID string, name string) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeEventsAPI,
+		Data: slackevents.EventsAPIEvent{
+			Type: slackevents.CallbackEvent,
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Type: string(slackevents.ChannelRename),
+				Data: &slackevents.ChannelRenameEvent{
+					Channel: slackevents.ChannelRenameInfo{ID: channelID, Name: name},
+				},
+			},
+		},
+	}
+}
+
+func appMentionEvent(mentionEvent slackevents.AppMentionEvent) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeEventsAPI,
+		Data: slackevents.EventsAPIEvent{
+			Type: slackevents.CallbackEvent,
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Data: &mentionEvent,
+			},
+		},
+	}
+}
+
+func appHomeOpenedEvent(userID string) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeEventsAPI,
+		Data: slackevents.EventsAPIEvent{
+			Type: slackevents.CallbackEvent,
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Data: &slackevents.AppHomeOpenedEvent{
+					User:    userID,
+					Channel: "D123",
+					Tab:     "home",
+				},
+			},
+		},
+	}
+}
+
+func shortcutEvent(
+	interactionType slack.InteractionType,
+	callbackID string,
+	message slack.Message,
+) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeInteractive,
+		Data: slack.InteractionCallback{
+			Type:       interactionType,
+			CallbackID: callbackID,
+			TriggerID:  "trigger",
+			User:       slack.User{ID: "DEF456"},
+			Channel: slack.Channel{
+				GroupConversation: slack.GroupConversation{
+					Conversation: slack.Conversation{ID: "ABC123"},
+				},
+			},
+			Message: message,
+		},
+	}
+}
+
 func slashCommandEvent(data slack.SlashCommand) socketmode.Event {
 	return socketmode.Event{
 		Type: socketmode.EventTypeSlashCommand,
@@ -141,6 +552,26 @@ func slashCommandEvent(data slack.SlashCommand) socketmode.Event {
 	}
 }
 
+func blockSuggestionEvent(
+	metadata slack.SlackMetadata,
+	blockID string,
+	query string,
+) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeInteractive,
+		Data: slack.InteractionCallback{
+			Type: slack.InteractionTypeBlockSuggestion,
+			Message: slack.Message{
+				Msg: slack.Msg{
+					Metadata: metadata,
+				},
+			},
+			BlockID: blockID,
+			Value:   query,
+		},
+	}
+}
+
 func messageInteractionEvent(
 	hash string,
 	timestamp string,
@@ -165,3 +596,42 @@ func messageInteractionEvent(
 		},
 	}
 }
+
+func viewSubmissionEvent(
+	view slack.ModalViewRequest,
+	values map[string]map[string]slack.BlockAction,
+) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeInteractive,
+		Data: slack.InteractionCallback{
+			Type: slack.InteractionTypeViewSubmission,
+			View: slack.View{
+				PrivateMetadata: view.PrivateMetadata,
+				State: &slack.ViewState{
+					Values: values,
+				},
+			},
+		},
+	}
+}
+
+func viewInteractionEvent(
+	view slack.View,
+	actionCallbacks slack.ActionCallbacks,
+) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeInteractive,
+		Data: slack.InteractionCallback{
+			Type:           slack.InteractionTypeBlockActions,
+			View:           view,
+			ActionCallback: actionCallbacks,
+		},
+	}
+}
+
+func interactionEvent(callback slack.InteractionCallback) socketmode.Event {
+	return socketmode.Event{
+		Type: socketmode.EventTypeInteractive,
+		Data: callback,
+	}
+}
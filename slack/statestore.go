@@ -0,0 +1,144 @@
+package slack
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrStateNotFound is returned by a StateStore when no state exists for a key,
+// or the state has expired.
+var ErrStateNotFound = errors.New("state not found")
+
+// StateStore stores the state of events between interactions.
+//
+// By default, state is embedded in message metadata and modal private metadata,
+// which Slack limits in size. When a StateStore is configured, state is saved to the
+// store and only an opaque key is sent to Slack.
+//
+// Stores are responsible for expiring state that is no longer needed.
+type StateStore interface {
+	Put(ctx context.Context, key string, state []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// stateReference is sent to Slack in place of event state when a StateStore is configured.
+type stateReference struct {
+	Key string `json:"state_key"`
+}
+
+// newStateKey returns a random key under which to save the state of an event.
+func newStateKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generating state key: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// saveState saves state to the store under key and returns a reference to send to Slack.
+// If store is nil, the state is returned unchanged.
+func saveState(ctx context.Context, store StateStore, key string, state []byte) ([]byte, error) {
+	if store == nil {
+		return state, nil
+	}
+
+	if err := store.Put(ctx, key, state); err != nil {
+		return nil, fmt.Errorf("saving state: %w", err)
+	}
+	return json.Marshal(stateReference{Key: key})
+}
+
+// loadState resolves state received from Slack.
+// State that was embedded directly, rather than saved to a store, is returned unchanged.
+func loadState(ctx context.Context, store StateStore, data []byte) ([]byte, error) {
+	if store == nil {
+		return data, nil
+	}
+
+	var ref stateReference
+	if err := json.Unmarshal(data, &ref); err != nil || ref.Key == "" {
+		return data, nil
+	}
+
+	state, err := store.Get(ctx, ref.Key)
+	if err != nil {
+		return nil, fmt.Errorf("loading state %q: %w", ref.Key, err)
+	}
+	return state, nil
+}
+
+var _ StateStore = &MemoryStateStore{}
+
+// MemoryStateStore is a StateStore that holds state in memory.
+// State is lost when the app restarts.
+type MemoryStateStore struct {
+	ttl time.Duration
+
+	mtx       sync.Mutex
+	states    map[string]memoryState
+	lastSweep time.Time
+}
+
+type memoryState struct {
+	state   []byte
+	expires time.Time
+}
+
+// NewMemoryStateStore creates a StateStore that holds state in memory.
+// State expires after ttl, if ttl is zero, state never expires.
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	return &MemoryStateStore{
+		ttl:       ttl,
+		states:    make(map[string]memoryState),
+		lastSweep: time.Now(),
+	}
+}
+
+// Put implements StateStore.
+func (m *MemoryStateStore) Put(ctx context.Context, key string, state []byte) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := time.Now()
+	entry := memoryState{
+		state: state,
+	}
+	if m.ttl > 0 {
+		entry.expires = now.Add(m.ttl)
+	}
+	m.states[key] = entry
+
+	// Remove expired state at most once per ttl
+	if m.ttl > 0 && now.Sub(m.lastSweep) > m.ttl {
+		for k, s := range m.states {
+			if now.After(s.expires) {
+				delete(m.states, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	return nil
+}
+
+// Get implements StateStore.
+func (m *MemoryStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	entry, ok := m.states[key]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(m.states, key)
+		return nil, ErrStateNotFound
+	}
+	return entry.state, nil
+}
@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var _ StateStore = &FileStateStore{}
+
+// FileStateStore is a StateStore that saves state as files in a directory.
+// State is kept when the app restarts.
+type FileStateStore struct {
+	dir string
+	ttl time.Duration
+
+	mtx       sync.Mutex
+	lastSweep time.Time
+}
+
+// NewFileStateStore creates a StateStore that saves state in the directory dir,
+// creating it if needed.
+// State expires after ttl, if ttl is zero, state never expires.
+func NewFileStateStore(dir string, ttl time.Duration) (*FileStateStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating state directory: %w", err)
+	}
+	return &FileStateStore{
+		dir:       dir,
+		ttl:       ttl,
+		lastSweep: time.Now(),
+	}, nil
+}
+
+// Put implements StateStore.
+func (f *FileStateStore) Put(ctx context.Context, key string, state []byte) error {
+	// Write to a temporary file first so partial state is never read
+	tmp, err := os.CreateTemp(f.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(state); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), f.path(key)); err != nil {
+		return err
+	}
+
+	f.sweep()
+	return nil
+}
+
+// Get implements StateStore.
+func (f *FileStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path := f.path(key)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if f.expired(info, time.Now()) {
+		os.Remove(path)
+		return nil, ErrStateNotFound
+	}
+
+	return os.ReadFile(path)
+}
+
+func (f *FileStateStore) path(key string) string {
+	return filepath.Join(f.dir, filepath.Base(key))
+}
+
+func (f *FileStateStore) expired(info fs.FileInfo, now time.Time) bool {
+	return f.ttl > 0 && now.Sub(info.ModTime()) > f.ttl
+}
+
+// sweep removes expired state at most once per ttl.
+func (f *FileStateStore) sweep() {
+	if f.ttl <= 0 {
+		return
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	now := time.Now()
+	if now.Sub(f.lastSweep) <= f.ttl {
+		return
+	}
+	f.lastSweep = now
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if f.expired(info, now) {
+			os.Remove(filepath.Join(f.dir, entry.Name()))
+		}
+	}
+}
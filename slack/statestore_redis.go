@@ -0,0 +1,236 @@
+package slack
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var _ StateStore = &RedisStateStore{}
+
+// RedisStateStore is a StateStore that saves state in Redis, or any server
+// that supports the Redis protocol.
+// Expiry of state is handled by the server.
+type RedisStateStore struct {
+	addr   string
+	prefix string
+	ttl    time.Duration
+
+	username  string
+	password  string
+	db        int
+	tlsConfig *tls.Config
+
+	mtx  sync.Mutex
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// RedisOption configures the connection to a Redis server.
+type RedisOption func(*RedisStateStore)
+
+// WithRedisPassword authenticates with the server using a password.
+// If username is empty, the default user is used.
+func WithRedisPassword(username string, password string) RedisOption {
+	return func(r *RedisStateStore) {
+		r.username = username
+		r.password = password
+	}
+}
+
+// WithRedisTLS connects to the server over TLS.
+// If config is nil, the default configuration is used.
+func WithRedisTLS(config *tls.Config) RedisOption {
+	return func(r *RedisStateStore) {
+		if config == nil {
+			config = &tls.Config{}
+		}
+		r.tlsConfig = config
+	}
+}
+
+// WithRedisDB selects the database used to store state.
+func WithRedisDB(db int) RedisOption {
+	return func(r *RedisStateStore) {
+		r.db = db
+	}
+}
+
+// WithRedisKeyPrefix sets the prefix for keys used to store state, so apps sharing
+// a database don't use the same keys.
+func WithRedisKeyPrefix(prefix string) RedisOption {
+	return func(r *RedisStateStore) {
+		r.prefix = prefix
+	}
+}
+
+// NewRedisStateStore creates a StateStore that saves state to the Redis server at addr.
+// Keys are prefixed with "spanner:", unless a prefix is set with WithRedisKeyPrefix.
+// State expires after ttl, if ttl is zero, state never expires.
+func NewRedisStateStore(addr string, ttl time.Duration, options ...RedisOption) *RedisStateStore {
+	r := &RedisStateStore{
+		addr:   addr,
+		prefix: "spanner:",
+		ttl:    ttl,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Put implements StateStore.
+func (r *RedisStateStore) Put(ctx context.Context, key string, state []byte) error {
+	args := []string{"SET", r.prefix + key, string(state)}
+	if r.ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(r.ttl.Milliseconds(), 10))
+	}
+
+	_, err := r.do(ctx, args...)
+	return err
+}
+
+// Get implements StateStore.
+func (r *RedisStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := r.do(ctx, "GET", r.prefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrStateNotFound
+	}
+	return reply, nil
+}
+
+// Close closes the connection to the server.
+func (r *RedisStateStore) Close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.conn == nil {
+		return nil
+	}
+	err := r.conn.Close()
+	r.conn = nil
+	return err
+}
+
+// do sends a command to the server and returns the reply.
+// A nil reply is returned for nil bulk strings.
+func (r *RedisStateStore) do(ctx context.Context, args ...string) ([]byte, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.conn == nil {
+		if err := r.connect(ctx); err != nil {
+			return nil, fmt.Errorf("connecting to redis: %w", err)
+		}
+	}
+
+	setDeadline(ctx, r.conn)
+	reply, err := r.roundTrip(args)
+	if err != nil {
+		// The connection may be in an unknown state, so reconnect on the next command
+		r.conn.Close()
+		r.conn = nil
+		return nil, fmt.Errorf("redis %v: %w", args[0], err)
+	}
+	return reply, nil
+}
+
+// connect opens a connection to the server, authenticating and selecting the database if needed.
+func (r *RedisStateStore) connect(ctx context.Context) error {
+	var (
+		conn net.Conn
+		err  error
+	)
+	if r.tlsConfig != nil {
+		d := tls.Dialer{Config: r.tlsConfig}
+		conn, err = d.DialContext(ctx, "tcp", r.addr)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", r.addr)
+	}
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+	r.rw = bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+
+	var setup [][]string
+	if r.password != "" {
+		if r.username != "" {
+			setup = append(setup, []string{"AUTH", r.username, r.password})
+		} else {
+			setup = append(setup, []string{"AUTH", r.password})
+		}
+	}
+	if r.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(r.db)})
+	}
+
+	setDeadline(ctx, conn)
+	for _, args := range setup {
+		if _, err := r.roundTrip(args); err != nil {
+			conn.Close()
+			r.conn = nil
+			return fmt.Errorf("%v: %w", args[0], err)
+		}
+	}
+	return nil
+}
+
+func setDeadline(ctx context.Context, conn net.Conn) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Time{})
+	}
+}
+
+func (r *RedisStateStore) roundTrip(args []string) ([]byte, error) {
+	fmt.Fprintf(r.rw, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(r.rw, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := r.rw.Flush(); err != nil {
+		return nil, err
+	}
+
+	line, err := r.rw.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, fmt.Errorf("malformed reply: %q", line)
+	}
+	line = line[:len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, fmt.Errorf("server error: %v", line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("malformed reply: %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r.rw, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	default:
+		return nil, fmt.Errorf("unexpected reply: %q", line)
+	}
+}
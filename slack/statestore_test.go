@@ -0,0 +1,334 @@
+package slack
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/theothertomelliott/spanner"
+)
+
+func testStateStore(t *testing.T, store StateStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("expected ErrStateNotFound for missing key, got %v", err)
+	}
+
+	if err := store.Put(ctx, "key", []byte(`{"a":"b"}`)); err != nil {
+		t.Fatalf("unexpected error putting state: %v", err)
+	}
+	state, err := store.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("unexpected error getting state: %v", err)
+	}
+	if string(state) != `{"a":"b"}` {
+		t.Errorf("unexpected state: %q", string(state))
+	}
+}
+
+func TestMemoryStateStore(t *testing.T) {
+	testStateStore(t, NewMemoryStateStore(time.Minute))
+}
+
+func TestMemoryStateStoreExpiry(t *testing.T) {
+	store := NewMemoryStateStore(time.Millisecond)
+	store.Put(context.Background(), "key", []byte("state"))
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := store.Get(context.Background(), "key"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("expected state to have expired, got %v", err)
+	}
+}
+
+func TestFileStateStore(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStateStore(t, store)
+}
+
+func TestRedisStateStore(t *testing.T) {
+	addr := fakeRedisServer(t, "", nil)
+	store := NewRedisStateStore(addr, time.Minute)
+	defer store.Close()
+
+	testStateStore(t, store)
+
+	// State saved with a different prefix should not be visible
+	other := NewRedisStateStore(addr, time.Minute, WithRedisKeyPrefix("other-app:"))
+	defer other.Close()
+	if _, err := other.Get(context.Background(), "key"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("expected state to be stored with the default prefix, got %v", err)
+	}
+}
+
+func TestRedisStateStoreAuthAndTLS(t *testing.T) {
+	// Borrow the test certificate from an HTTPS test server
+	srv := httptest.NewUnstartedServer(nil)
+	srv.StartTLS()
+	defer srv.Close()
+	clientConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig
+
+	addr := fakeRedisServer(t, "secret", srv.TLS)
+
+	unauthenticated := NewRedisStateStore(addr, time.Minute, WithRedisTLS(clientConfig))
+	defer unauthenticated.Close()
+	if err := unauthenticated.Put(context.Background(), "key", []byte("state")); err == nil {
+		t.Errorf("expected an error without a password")
+	}
+
+	store := NewRedisStateStore(addr, time.Minute,
+		WithRedisTLS(clientConfig),
+		WithRedisPassword("", "secret"),
+		WithRedisDB(2),
+	)
+	defer store.Close()
+	testStateStore(t, store)
+
+	// State in other databases should not be visible
+	other := NewRedisStateStore(addr, time.Minute, WithRedisTLS(clientConfig), WithRedisPassword("default", "secret"))
+	defer other.Close()
+	if _, err := other.Get(context.Background(), "key"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("expected state to be stored in the selected database, got %v", err)
+	}
+}
+
+// fakeRedisServer starts a server that supports the AUTH, SELECT, GET and SET commands
+// and returns its address.
+// If password is set, clients must authenticate, and if tlsConfig is set, the server uses TLS.
+func fakeRedisServer(t *testing.T, password string, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	if tlsConfig != nil {
+		l = tls.NewListener(l, tlsConfig)
+	}
+
+	var mtx sync.Mutex
+	data := make(map[string]string)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+				authenticated := password == ""
+				db := "0"
+				for {
+					args, err := readRedisCommand(rw.Reader)
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(args[0])
+					if !authenticated && command != "AUTH" {
+						rw.WriteString("-NOAUTH Authentication required.\r\n")
+						rw.Flush()
+						continue
+					}
+
+					mtx.Lock()
+					switch command {
+					case "AUTH":
+						if args[len(args)-1] == password {
+							authenticated = true
+							rw.WriteString("+OK\r\n")
+						} else {
+							rw.WriteString("-WRONGPASS invalid password\r\n")
+						}
+					case "SELECT":
+						db = args[1]
+						rw.WriteString("+OK\r\n")
+					case "SET":
+						data[db+":"+args[1]] = args[2]
+						rw.WriteString("+OK\r\n")
+					case "GET":
+						if value, ok := data[db+":"+args[1]]; ok {
+							fmt.Fprintf(rw, "$%d\r\n%s\r\n", len(value), value)
+						} else {
+							rw.WriteString("$-1\r\n")
+						}
+					default:
+						rw.WriteString("-ERR unknown command\r\n")
+					}
+					mtx.Unlock()
+					rw.Flush()
+				}
+			}(conn)
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func readRedisCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	for i := 0; i < count; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(r, arg); err != nil {
+			return nil, err
+		}
+		args = append(args, string(arg[:size]))
+	}
+	return args, nil
+}
+
+// TestStateStoreInteraction verifies that interactions can be handled when
+// state is saved to a store rather than sent to Slack.
+func TestStateStoreInteraction(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := newAppWithClient(
+		client,
+		AppConfig{
+			EventInterceptor: client.EventInterceptor,
+			StateStore:       NewMemoryStateStore(time.Minute),
+		},
+		client.Events,
+	)
+
+	go func() {
+		err := testApp.Run(handler)
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(
+		slackevents.MessageEvent{
+			Text:    "hello",
+			Channel: "ABC123",
+			User:    "DEF456",
+		},
+	))
+
+	if len(client.messagesSent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(client.messagesSent))
+	}
+	msg := client.messagesSent[0]
+	client.messagesSent = nil
+
+	metadata, _ := msg.metadata.EventPayload["metadata"].(string)
+	if !strings.HasPrefix(metadata, `{"state_key":`) {
+		t.Errorf("expected metadata to reference stored state, got: %v", metadata)
+	}
+
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		"timestamp",
+		msg.metadata,
+		slack.ActionCallbacks{},
+		&slack.BlockActionStates{
+			Values: map[string]map[string]slack.BlockAction{
+				fmt.Sprintf("input-0-%v", hashstr(strings.Join([]string{"a", "b", "c"}, ","))): {
+					"x": slack.BlockAction{
+						SelectedOption: slack.OptionBlockObject{
+							Value: "c",
+						},
+					},
+				},
+			},
+		},
+	))
+
+	if len(client.messagesSent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(client.messagesSent))
+	}
+}
+
+// TestExpiredStateInteraction verifies that an interaction whose state has expired
+// is acknowledged without calling the handler rather than crashing the app.
+func TestExpiredStateInteraction(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := newAppWithClient(
+		client,
+		AppConfig{
+			EventInterceptor: client.EventInterceptor,
+			StateStore:       NewMemoryStateStore(time.Millisecond),
+		},
+		client.Events,
+	)
+
+	var calls int
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			calls++
+			handler(ctx, ev)
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(
+		slackevents.MessageEvent{
+			Text:    "hello",
+			Channel: "ABC123",
+			User:    "DEF456",
+		},
+	))
+	if len(client.messagesSent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(client.messagesSent))
+	}
+	msg := client.messagesSent[0]
+	client.messagesSent = nil
+	client.acked = nil
+
+	time.Sleep(5 * time.Millisecond)
+
+	client.SendEventToApp(messageInteractionEvent(
+		"hash",
+		"timestamp",
+		msg.metadata,
+		slack.ActionCallbacks{},
+		&slack.BlockActionStates{},
+	))
+
+	if len(client.messagesSent) != 0 || len(client.messagesUpdated) != 0 {
+		t.Errorf("expected no messages for a stale interaction, got %d sent and %d updated", len(client.messagesSent), len(client.messagesUpdated))
+	}
+	if len(client.acked) != 1 {
+		t.Errorf("expected the stale interaction to be acknowledged, got %d acks", len(client.acked))
+	}
+	if calls != 1 {
+		t.Errorf("expected the handler not to be called for the stale interaction, got %d calls", calls)
+	}
+}
@@ -0,0 +1,57 @@
+package slack
+
+import (
+	"context"
+	"log"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
+)
+
+var _ action = &blockSuggestion{}
+
+// blockSuggestion handles a request to load options for an external select.
+// The handler is run to find the select the request is for, and only the options
+// are returned to Slack - no other actions are performed.
+type blockSuggestion struct {
+	blockID string
+	query   string
+
+	loader spanner.OptionLoader
+
+	errFunc spanner.ErrorFunc
+}
+
+func (b *blockSuggestion) ErrorFunc(ef spanner.ErrorFunc) {
+	b.errFunc = ef
+}
+
+func (b *blockSuggestion) getErrorFunc() spanner.ErrorFunc {
+	return b.errFunc
+}
+
+// Type implements action.
+func (*blockSuggestion) Type() string {
+	return "block_suggestion"
+}
+
+// Data implements action.
+func (b *blockSuggestion) Data() interface{} {
+	// TODO: This should be more well-defined
+	return map[string]interface{}{
+		"block_id": b.blockID,
+		"query":    b.query,
+	}
+}
+
+// exec implements action.
+func (b *blockSuggestion) exec(ctx context.Context, req request) (interface{}, error) {
+	if b.loader == nil {
+		log.Printf("no external select found for block %q", b.blockID)
+		return slack.OptionsResponse{}, nil
+	}
+
+	return slack.OptionsResponse{
+		Options: optionBlockObjects(b.loader(ctx, b.query)),
+	}, nil
+}
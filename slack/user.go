@@ -2,16 +2,42 @@ package slack
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/theothertomelliott/spanner"
 )
 
 type user struct {
 	client socketClient
+	sender *MessageSender
 	Loaded bool `json:"loaded"`
 
 	IDInternal       string `json:"id"`
 	NameInternal     string `json:"display_name"`
 	RealNameInternal string `json:"real_name"`
 	EmailInternal    string `json:"email"`
+
+	TitleInternal        string         `json:"title,omitempty"`
+	TimeZoneInternal     string         `json:"tz,omitempty"`
+	TimeZoneLabel        string         `json:"tz_label,omitempty"`
+	TimeZoneOffset       int            `json:"tz_offset,omitempty"`
+	AvatarURLs           map[int]string `json:"avatars,omitempty"`
+	IsBotInternal        bool           `json:"is_bot,omitempty"`
+	IsAdminInternal      bool           `json:"is_admin,omitempty"`
+	IsRestrictedInternal bool           `json:"is_restricted,omitempty"`
+
+	// Presence and custom fields are loaded separately, and only when needed
+	presence       string
+	presenceLoaded bool
+	presenceErr    error
+	profileFields  map[string]string
+	profileLoaded  bool
+	profileErr     error
+
+	loadErr error
 }
 
 func (u *user) ID() string {
@@ -33,19 +59,157 @@ func (u *user) Email(ctx context.Context) string {
 	return u.EmailInternal
 }
 
-func (u *user) load(ctx context.Context) {
+func (u *user) Title(ctx context.Context) string {
+	u.load(ctx)
+	return u.TitleInternal
+}
+
+func (u *user) TimeZone(ctx context.Context) *time.Location {
+	u.load(ctx)
+	if u.TimeZoneInternal != "" {
+		if loc, err := time.LoadLocation(u.TimeZoneInternal); err == nil {
+			return loc
+		}
+	}
+	if u.TimeZoneLabel != "" || u.TimeZoneOffset != 0 {
+		return time.FixedZone(u.TimeZoneLabel, u.TimeZoneOffset)
+	}
+	return time.UTC
+}
+
+// avatarSizes are the sizes of avatar image provided by Slack, in ascending order.
+var avatarSizes = []int{24, 32, 48, 72, 192, 512}
+
+// avatarOriginal is the key for the originally uploaded avatar image.
+const avatarOriginal = 0
+
+func (u *user) AvatarURL(ctx context.Context, size int) string {
+	u.load(ctx)
+	for _, s := range avatarSizes {
+		if s >= size && u.AvatarURLs[s] != "" {
+			return u.AvatarURLs[s]
+		}
+	}
+	// Fall back to the largest available image
+	if original := u.AvatarURLs[avatarOriginal]; original != "" {
+		return original
+	}
+	for i := len(avatarSizes) - 1; i >= 0; i-- {
+		if url := u.AvatarURLs[avatarSizes[i]]; url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+func (u *user) IsBot(ctx context.Context) bool {
+	u.load(ctx)
+	return u.IsBotInternal
+}
+
+func (u *user) IsAdmin(ctx context.Context) bool {
+	u.load(ctx)
+	return u.IsAdminInternal
+}
+
+func (u *user) IsRestricted(ctx context.Context) bool {
+	u.load(ctx)
+	return u.IsRestrictedInternal
+}
+
+func (u *user) Presence(ctx context.Context) string {
+	if u.presenceLoaded || u.presenceErr != nil {
+		return u.presence
+	}
+
+	presence, err := u.client.GetUserPresenceContext(ctx, u.IDInternal)
+	if err != nil {
+		u.presenceErr = fmt.Errorf("loading presence for user %v: %w", u.IDInternal, renderSlackError(err))
+		log.Print(u.presenceErr)
+		return ""
+	}
+	u.presence = presence.Presence
+	u.presenceLoaded = true
+	return u.presence
+}
+
+func (u *user) ProfileFields(ctx context.Context) map[string]string {
+	if u.profileLoaded || u.profileErr != nil {
+		return u.profileFields
+	}
+
+	profile, err := u.client.GetUserProfileContext(ctx, &slack.GetUserProfileParameters{
+		UserID:        u.IDInternal,
+		IncludeLabels: true,
+	})
+	if err != nil {
+		u.profileErr = fmt.Errorf("loading profile for user %v: %w", u.IDInternal, renderSlackError(err))
+		log.Print(u.profileErr)
+		return nil
+	}
+
+	u.profileFields = make(map[string]string)
+	for id, field := range profile.Fields.ToMap() {
+		name := field.Label
+		if name == "" {
+			name = id
+		}
+		u.profileFields[name] = field.Value
+	}
+	u.profileLoaded = true
+	return u.profileFields
+}
+
+func (u *user) SendMessage() spanner.Message {
+	return u.sender.SendDirectMessage(u.IDInternal)
+}
+
+func (u *user) Load(ctx context.Context) error {
 	if u.Loaded {
-		return
+		return nil
+	}
+	// Don't repeat failed requests for the same event
+	if u.loadErr != nil {
+		return u.loadErr
 	}
 
 	user, err := u.client.GetUserInfoContext(ctx, u.IDInternal)
 	if err != nil {
-		// TODO: Hoist up this error somehow
-		panic(err)
+		u.loadErr = fmt.Errorf("loading user %v: %w", u.IDInternal, renderSlackError(err))
+		return u.loadErr
 	}
 
 	u.NameInternal = user.Name
 	u.RealNameInternal = user.RealName
 	u.EmailInternal = user.Profile.Email
+	u.TitleInternal = user.Profile.Title
+	u.TimeZoneInternal = user.TZ
+	u.TimeZoneLabel = user.TZLabel
+	u.TimeZoneOffset = user.TZOffset
+	u.AvatarURLs = make(map[int]string)
+	for size, url := range map[int]string{
+		avatarOriginal: user.Profile.ImageOriginal,
+		24:             user.Profile.Image24,
+		32:             user.Profile.Image32,
+		48:             user.Profile.Image48,
+		72:             user.Profile.Image72,
+		192:            user.Profile.Image192,
+		512:            user.Profile.Image512,
+	} {
+		if url != "" {
+			u.AvatarURLs[size] = url
+		}
+	}
+	u.IsBotInternal = user.IsBot
+	u.IsAdminInternal = user.IsAdmin || user.IsOwner
+	u.IsRestrictedInternal = user.IsRestricted || user.IsUltraRestricted
 	u.Loaded = true
+	return nil
+}
+
+// load loads the user, logging any error.
+func (u *user) load(ctx context.Context) {
+	if err := u.Load(ctx); err != nil {
+		log.Print(err)
+	}
 }
@@ -0,0 +1,230 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/theothertomelliott/spanner"
+)
+
+func TestUserAndChannelLoad(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	client.users = map[string]*slack.User{
+		"DEF456": {ID: "DEF456", Name: "jdoe", RealName: "Jane Doe"},
+	}
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			msg := ev.ReceiveMessage()
+			if msg == nil {
+				return
+			}
+			reply := ev.SendMessage(msg.Channel().ID())
+			if err := msg.User().Load(ctx); err != nil {
+				reply.PlainText("user error: " + err.Error())
+			} else {
+				reply.PlainText("user: " + msg.User().RealName(ctx))
+			}
+			if err := msg.Channel().Load(ctx); err != nil {
+				reply.PlainText("channel error: " + err.Error())
+			} else {
+				reply.PlainText("channel: " + msg.Channel().Name(ctx))
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:    "hello",
+		Channel: "ABC123",
+		User:    "DEF456",
+	}))
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:    "hello",
+		Channel: "ABC123",
+		User:    "UNKNOWN",
+	}))
+
+	if len(client.messagesSent) != 2 {
+		t.Fatalf("expected two messages to be sent, got %d", len(client.messagesSent))
+	}
+
+	found, _ := json.Marshal(client.messagesSent[0].blocks)
+	for _, expected := range []string{"user: Jane Doe", "channel: channel-ABC123"} {
+		if !strings.Contains(string(found), expected) {
+			t.Errorf("expected %q in message, got: %v", expected, string(found))
+		}
+	}
+
+	notFound, _ := json.Marshal(client.messagesSent[1].blocks)
+	if !strings.Contains(string(notFound), "user error: loading user UNKNOWN") {
+		t.Errorf("expected user error in message, got: %v", string(notFound))
+	}
+}
+
+// TestHandlerPanic verifies that a panic in a handler is recovered and the event acknowledged.
+func TestHandlerPanic(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	testApp := client.CreateApp()
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if msg := ev.ReceiveMessage(); msg != nil {
+				if msg.Text() == "panic" {
+					panic("handler failure")
+				}
+				ev.SendMessage(msg.Channel().ID()).PlainText("still running")
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	panicEvent := messageEvent(slackevents.MessageEvent{
+		Text:    "panic",
+		Channel: "ABC123",
+		User:    "DEF456",
+	})
+	panicEvent.Request = &socketmode.Request{EnvelopeID: "panic"}
+	client.SendEventToApp(panicEvent)
+
+	if len(client.acked) != 1 {
+		t.Errorf("expected the failed event to be acknowledged, got %d acks", len(client.acked))
+	}
+
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:    "hello",
+		Channel: "ABC123",
+		User:    "DEF456",
+	}))
+
+	if len(client.messagesSent) != 1 {
+		t.Errorf("expected app to continue handling events, got %d messages", len(client.messagesSent))
+	}
+}
+
+func TestEventInterceptorPanic(t *testing.T) {
+	client := newTestClient([]string{"ABC123"})
+	var events int
+	testApp := newAppWithClient(
+		client,
+		AppConfig{
+			EventInterceptor: func(ctx context.Context, process func(context.Context)) {
+				defer func() {
+					client.postEvent <- struct{}{}
+				}()
+				events++
+				if events == 1 {
+					panic("interceptor failure")
+				}
+				process(ctx)
+			},
+		},
+		client.Events,
+	)
+
+	go func() {
+		err := testApp.Run(func(ctx context.Context, ev spanner.Event) {
+			if msg := ev.ReceiveMessage(); msg != nil {
+				ev.SendMessage(msg.Channel().ID()).PlainText("still running")
+			}
+		})
+		if err != nil {
+			t.Errorf("error running app: %v", err)
+		}
+	}()
+
+	panicEvent := messageEvent(slackevents.MessageEvent{
+		Text:    "hello",
+		Channel: "ABC123",
+		User:    "DEF456",
+	})
+	panicEvent.Request = &socketmode.Request{EnvelopeID: "panic"}
+	client.SendEventToApp(panicEvent)
+
+	// The interceptor signals the end of the event before the panic is
+	// recovered, but events in the same channel are handled in order, so the
+	// failed event has been acknowledged once this one is handled.
+	client.SendEventToApp(messageEvent(slackevents.MessageEvent{
+		Text:    "hello",
+		Channel: "ABC123",
+		User:    "DEF456",
+	}))
+
+	client.ackMtx.Lock()
+	acked := len(client.acked)
+	client.ackMtx.Unlock()
+	if acked != 2 {
+		t.Errorf("expected both events to be acknowledged, got %d acks", acked)
+	}
+
+	if len(client.messagesSent) != 1 {
+		t.Errorf("expected app to continue handling events, got %d messages", len(client.messagesSent))
+	}
+}
+
+func TestUserProfile(t *testing.T) {
+	profile := slack.UserProfile{
+		Title:    "On-call engineer",
+		Image48:  "https://example.com/48.png",
+		Image192: "https://example.com/192.png",
+	}
+	profile.Fields.SetMap(map[string]slack.UserProfileCustomField{
+		"Xf01": {Label: "Team", Value: "Platform"},
+	})
+
+	client := newTestClient([]string{"ABC123"})
+	client.users = map[string]*slack.User{
+		"DEF456": {
+			ID:           "DEF456",
+			TZ:           "America/New_York",
+			Profile:      profile,
+			IsRestricted: true,
+		},
+	}
+
+	u := &user{client: client, IDInternal: "DEF456"}
+	ctx := context.Background()
+
+	if title := u.Title(ctx); title != "On-call engineer" {
+		t.Errorf("unexpected title: %q", title)
+	}
+	if tz := u.TimeZone(ctx); tz.String() != "America/New_York" {
+		t.Errorf("unexpected time zone: %v", tz)
+	}
+	if url := u.AvatarURL(ctx, 64); url != "https://example.com/192.png" {
+		t.Errorf("unexpected avatar for size 64: %q", url)
+	}
+	if url := u.AvatarURL(ctx, 1024); url != "https://example.com/192.png" {
+		t.Errorf("expected largest avatar for size 1024, got %q", url)
+	}
+	if u.IsBot(ctx) || u.IsAdmin(ctx) || !u.IsRestricted(ctx) {
+		t.Errorf("expected a restricted, non-admin, human user")
+	}
+	if presence := u.Presence(ctx); presence != "active" {
+		t.Errorf("unexpected presence: %q", presence)
+	}
+	if fields := u.ProfileFields(ctx); fields["Team"] != "Platform" {
+		t.Errorf("unexpected profile fields: %v", fields)
+	}
+	if client.userInfoRequests != 1 {
+		t.Errorf("expected user info to be requested once, got %d", client.userInfoRequests)
+	}
+
+	// Unknown users should default to UTC
+	unknown := &user{client: client, IDInternal: "UNKNOWN"}
+	if tz := unknown.TimeZone(ctx); tz != time.UTC {
+		t.Errorf("expected UTC for unknown user, got %v", tz)
+	}
+}
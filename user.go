@@ -1,10 +1,40 @@
 package spanner
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// User represents a Slack user.
+// Details of the user are loaded from Slack when first needed. Load returns any error loading the user,
+// if it cannot be loaded, Name, RealName and Email will return empty strings.
+// SendMessage creates a direct message to the user.
 type User interface {
 	ID() string
 	Name(context.Context) string
 	RealName(context.Context) string
 	Email(context.Context) string
+
+	// Title returns the user's job title from their profile.
+	Title(context.Context) string
+	// TimeZone returns the user's local time zone, or UTC if it is not known.
+	TimeZone(context.Context) *time.Location
+	// AvatarURL returns the URL of the smallest avatar image that is at least size pixels square,
+	// or the largest available image if none are big enough.
+	AvatarURL(ctx context.Context, size int) string
+
+	// IsBot returns true if the user is a bot.
+	IsBot(context.Context) bool
+	// IsAdmin returns true if the user is an admin or owner of the workspace.
+	IsAdmin(context.Context) bool
+	// IsRestricted returns true if the user is a guest, with access to only some channels.
+	IsRestricted(context.Context) bool
+
+	// Presence returns "active" or "away", this is requested from Slack for each event.
+	Presence(context.Context) string
+	// ProfileFields returns the custom fields from the user's profile, keyed by label.
+	ProfileFields(context.Context) map[string]string
+
+	Load(context.Context) error
+	SendMessage() Message
 }